@@ -2,10 +2,12 @@ package main
 
 import (
 	"bufio"
+	"context"
 	"encoding/gob"
 	"fmt"
 	"log"
 	"os"
+	"regexp"
 	"slices"
 	"strings"
 
@@ -14,9 +16,13 @@ import (
 	"github.com/kwohlfahrt/tf-k8s/internal/provider"
 	"github.com/kwohlfahrt/tf-k8s/internal/types"
 	flag "github.com/spf13/pflag"
+	strcase "github.com/stoewer/go-strcase"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	runtimeschema "k8s.io/apimachinery/pkg/runtime/schema"
 	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/openapi3"
 	"k8s.io/kube-openapi/pkg/spec3"
 	"k8s.io/kube-openapi/pkg/validation/spec"
@@ -28,6 +34,9 @@ type openapiConfig struct {
 		Property string      `json:"property"`
 		Value    interface{} `json:"value"`
 	} `json:"defaults"`
+	// Fields that are immutable, but not marked as such in the schema, by group
+	// and kind.
+	ImmutableFields map[string]map[string][]string `json:"immutableFields"`
 }
 
 var kubeconfig *string = flag.String("kubeconfig", os.Getenv("KUBECONFIG"), "Kubernetes config file path")
@@ -64,6 +73,74 @@ func getSchema(openapi *spec3.OpenAPI, gv runtimeschema.GroupVersion, resource m
 	return schema, nil
 }
 
+var crdGvr = runtimeschema.GroupVersionResource{
+	Group:    "apiextensions.k8s.io",
+	Version:  "v1",
+	Resource: "customresourcedefinitions",
+}
+
+var invalidAttributeChars = regexp.MustCompile("[^a-z0-9_]+")
+
+// getPrinterColumns fetches the `additionalPrinterColumns` of a resource, if it
+// is defined by a CRD.
+func getPrinterColumns(client *dynamic.DynamicClient, gv runtimeschema.GroupVersion, resource metav1.APIResource) ([]generic.PrinterColumn, error) {
+	if gv.Group == "" {
+		return nil, nil
+	}
+
+	crd, err := client.Resource(crdGvr).
+		Get(context.Background(), fmt.Sprintf("%s.%s", resource.Name, gv.Group), metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	versions, _, err := unstructured.NestedSlice(crd.Object, "spec", "versions")
+	if err != nil {
+		return nil, err
+	}
+	for _, version := range versions {
+		version, ok := version.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("expected version object, got %T", version)
+		}
+		if name, _, _ := unstructured.NestedString(version, "name"); name != gv.Version {
+			continue
+		}
+
+		rawColumns, _, err := unstructured.NestedSlice(version, "additionalPrinterColumns")
+		if err != nil {
+			return nil, err
+		}
+		columns := make([]generic.PrinterColumn, 0, len(rawColumns))
+		attributes := make(map[string]bool, len(rawColumns))
+		for _, rawColumn := range rawColumns {
+			rawColumn, ok := rawColumn.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("expected column object, got %T", rawColumn)
+			}
+			var column generic.PrinterColumn
+			column.Name, _, _ = unstructured.NestedString(rawColumn, "name")
+			column.Type, _, _ = unstructured.NestedString(rawColumn, "type")
+			column.Description, _, _ = unstructured.NestedString(rawColumn, "description")
+			column.JSONPath, _, _ = unstructured.NestedString(rawColumn, "jsonPath")
+
+			attribute := invalidAttributeChars.ReplaceAllString(strcase.SnakeCase(column.Name), "_")
+			attribute = strings.Trim(attribute, "_")
+			if attribute == "" || attributes[attribute] {
+				log.Printf("skipping printer column %q of %s, its attribute name is empty or not unique", column.Name, resource.Kind)
+				continue
+			}
+			attributes[attribute] = true
+			column.Attribute = attribute
+			columns = append(columns, column)
+		}
+		return columns, nil
+	}
+	return nil, nil
+}
+
 func main() {
 	flag.Parse()
 
@@ -107,6 +184,10 @@ func main() {
 		log.Fatal(err.Error())
 	}
 	root := openapi3.NewRoot(discoveryClient.OpenAPIV3())
+	dynamicClient, err := provider.MakeDynamicClient(kubeconfigBytes)
+	if err != nil {
+		log.Fatal(err.Error())
+	}
 
 	_, resourceLists, err := discoveryClient.ServerGroupsAndResources()
 	if err != nil {
@@ -168,13 +249,36 @@ func main() {
 				delete(metaTyp.AttrTypes, "namespace")
 			}
 
+			// Status is set by the server, so is exposed separately from the manifest
+			var statusTyp types.KubernetesType
+			if typ, found := objectTyp.AttrTypes["status"]; found {
+				if statusTyp, ok = typ.(types.KubernetesType); !ok {
+					log.Fatalf("expected KubernetesType at .status, got %T", typ)
+				}
+				delete(objectTyp.AttrTypes, "status")
+			}
+
+			for _, field := range config.ImmutableFields[gv.Group][resource.Kind] {
+				objectTyp, err = objectTyp.WithImmutableField(strings.Split(field, "."))
+				if err != nil {
+					log.Fatalf("unable to mark %s of %s as immutable: %s", field, resource.Kind, err.Error())
+				}
+			}
+
+			printerColumns, err := getPrinterColumns(dynamicClient, gv, resource)
+			if err != nil {
+				log.Fatalf("unable to get printer columns of %s: %s", resource.Kind, err.Error())
+			}
+
 			info := generic.TypeInfo{
-				Group:      gv.Group,
-				Version:    gv.Version,
-				Kind:       resource.Kind,
-				Resource:   resource.Name,
-				Namespaced: resource.Namespaced,
-				Schema:     objectTyp,
+				Group:          gv.Group,
+				Version:        gv.Version,
+				Kind:           resource.Kind,
+				Resource:       resource.Name,
+				Namespaced:     resource.Namespaced,
+				Schema:         objectTyp,
+				Status:         statusTyp,
+				PrinterColumns: printerColumns,
 			}
 
 			err = enc.Encode(info)
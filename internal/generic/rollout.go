@@ -0,0 +1,323 @@
+package generic
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	runtimeschema "k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// RolloutStatus summarises whether an object has finished reconciling, with
+// the same meaning as the statuses computed by kstatus
+// (sigs.k8s.io/cli-utils/pkg/kstatus).
+type RolloutStatus string
+
+const (
+	RolloutInProgress  RolloutStatus = "InProgress"
+	RolloutFailed      RolloutStatus = "Failed"
+	RolloutCurrent     RolloutStatus = "Current"
+	RolloutTerminating RolloutStatus = "Terminating"
+)
+
+type rolloutFunc func(obj *unstructured.Unstructured) (RolloutStatus, string, error)
+
+var rolloutFuncs = map[runtimeschema.GroupKind]rolloutFunc{
+	{Group: "apps", Kind: "Deployment"}:        deploymentRollout,
+	{Group: "apps", Kind: "StatefulSet"}:       statefulSetRollout,
+	{Group: "apps", Kind: "DaemonSet"}:         daemonSetRollout,
+	{Group: "apps", Kind: "ReplicaSet"}:        replicaSetRollout,
+	{Group: "batch", Kind: "Job"}:              jobRollout,
+	{Group: "", Kind: "PersistentVolumeClaim"}: pvcRollout,
+	{Group: "", Kind: "Service"}:               serviceRollout,
+}
+
+// Rollout computes the rollout status of an object, along with a
+// human-readable message describing it. Built-in workload kinds are checked
+// against their status fields, other kinds only by their `Ready`, `Reconciling`
+// and `Stalled` conditions.
+func Rollout(obj *unstructured.Unstructured) (RolloutStatus, string, error) {
+	if obj.GetDeletionTimestamp() != nil {
+		return RolloutTerminating, "Resource scheduled for deletion", nil
+	}
+
+	observedGeneration, found, err := unstructured.NestedInt64(obj.Object, "status", "observedGeneration")
+	if err != nil {
+		return "", "", err
+	}
+	if found && observedGeneration < obj.GetGeneration() {
+		message := fmt.Sprintf("%s generation is %d, but latest observed generation is %d", obj.GetKind(), obj.GetGeneration(), observedGeneration)
+		return RolloutInProgress, message, nil
+	}
+
+	if f, ok := rolloutFuncs[obj.GroupVersionKind().GroupKind()]; ok {
+		return f(obj)
+	}
+	return conditionsRollout(obj)
+}
+
+type condition struct {
+	status  string
+	reason  string
+	message string
+}
+
+func getCondition(obj *unstructured.Unstructured, conditionType string) (*condition, error) {
+	conditions, _, err := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range conditions {
+		c, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if t, _, _ := unstructured.NestedString(c, "type"); t != conditionType {
+			continue
+		}
+		var result condition
+		result.status, _, _ = unstructured.NestedString(c, "status")
+		result.reason, _, _ = unstructured.NestedString(c, "reason")
+		result.message, _, _ = unstructured.NestedString(c, "message")
+		return &result, nil
+	}
+	return nil, nil
+}
+
+func conditionsRollout(obj *unstructured.Unstructured) (RolloutStatus, string, error) {
+	stalled, err := getCondition(obj, "Stalled")
+	if err != nil {
+		return "", "", err
+	}
+	if stalled != nil && stalled.status == "True" {
+		return RolloutFailed, stalled.message, nil
+	}
+
+	reconciling, err := getCondition(obj, "Reconciling")
+	if err != nil {
+		return "", "", err
+	}
+	if reconciling != nil && reconciling.status == "True" {
+		return RolloutInProgress, reconciling.message, nil
+	}
+
+	ready, err := getCondition(obj, "Ready")
+	if err != nil {
+		return "", "", err
+	}
+	if ready != nil && ready.status != "True" {
+		return RolloutInProgress, ready.message, nil
+	}
+
+	return RolloutCurrent, "Resource is current", nil
+}
+
+// getInt64s reads integer fields from `status`, treating missing fields as 0.
+func getInt64s(obj *unstructured.Unstructured, fields ...string) ([]int64, error) {
+	values := make([]int64, len(fields))
+	for i, field := range fields {
+		value, _, err := unstructured.NestedInt64(obj.Object, "status", field)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = value
+	}
+	return values, nil
+}
+
+func specReplicas(obj *unstructured.Unstructured) (int64, error) {
+	replicas, found, err := unstructured.NestedInt64(obj.Object, "spec", "replicas")
+	if !found {
+		replicas = 1
+	}
+	return replicas, err
+}
+
+func deploymentRollout(obj *unstructured.Unstructured) (RolloutStatus, string, error) {
+	progressing, err := getCondition(obj, "Progressing")
+	if err != nil {
+		return "", "", err
+	}
+	if progressing != nil && progressing.reason == "ProgressDeadlineExceeded" {
+		return RolloutFailed, "Progress deadline exceeded", nil
+	}
+
+	replicas, err := specReplicas(obj)
+	if err != nil {
+		return "", "", err
+	}
+	status, err := getInt64s(obj, "replicas", "updatedReplicas", "readyReplicas", "availableReplicas")
+	if err != nil {
+		return "", "", err
+	}
+	statusReplicas, updated, ready, available := status[0], status[1], status[2], status[3]
+
+	switch {
+	case updated < replicas:
+		return RolloutInProgress, fmt.Sprintf("Updated: %d/%d", updated, replicas), nil
+	case statusReplicas > updated:
+		return RolloutInProgress, fmt.Sprintf("Pending termination: %d", statusReplicas-updated), nil
+	case available < updated:
+		return RolloutInProgress, fmt.Sprintf("Available: %d/%d", available, updated), nil
+	case ready < updated:
+		return RolloutInProgress, fmt.Sprintf("Ready: %d/%d", ready, updated), nil
+	}
+	return RolloutCurrent, fmt.Sprintf("Deployment is available. Replicas: %d", statusReplicas), nil
+}
+
+func statefulSetRollout(obj *unstructured.Unstructured) (RolloutStatus, string, error) {
+	strategy, _, err := unstructured.NestedString(obj.Object, "spec", "updateStrategy", "type")
+	if err != nil {
+		return "", "", err
+	}
+	if strategy == "OnDelete" {
+		return RolloutCurrent, "StatefulSet is using the OnDelete update strategy", nil
+	}
+
+	replicas, err := specReplicas(obj)
+	if err != nil {
+		return "", "", err
+	}
+	partition, _, err := unstructured.NestedInt64(obj.Object, "spec", "updateStrategy", "rollingUpdate", "partition")
+	if err != nil {
+		return "", "", err
+	}
+	status, err := getInt64s(obj, "replicas", "readyReplicas", "currentReplicas", "updatedReplicas")
+	if err != nil {
+		return "", "", err
+	}
+	statusReplicas, ready, current, updated := status[0], status[1], status[2], status[3]
+
+	switch {
+	case statusReplicas < replicas:
+		return RolloutInProgress, fmt.Sprintf("Replicas: %d/%d", statusReplicas, replicas), nil
+	case ready < replicas:
+		return RolloutInProgress, fmt.Sprintf("Ready: %d/%d", ready, replicas), nil
+	}
+
+	if partition > 0 {
+		if expected := replicas - partition; updated < expected {
+			return RolloutInProgress, fmt.Sprintf("Updated: %d/%d (partition %d)", updated, expected, partition), nil
+		}
+		return RolloutCurrent, fmt.Sprintf("Partitioned roll out complete. Updated: %d", updated), nil
+	}
+
+	currentRevision, _, _ := unstructured.NestedString(obj.Object, "status", "currentRevision")
+	updateRevision, _, _ := unstructured.NestedString(obj.Object, "status", "updateRevision")
+	if current < replicas || currentRevision != updateRevision {
+		return RolloutInProgress, fmt.Sprintf("Current: %d/%d", current, replicas), nil
+	}
+	return RolloutCurrent, fmt.Sprintf("All replicas scheduled as expected. Replicas: %d", statusReplicas), nil
+}
+
+func daemonSetRollout(obj *unstructured.Unstructured) (RolloutStatus, string, error) {
+	// Until the controller has observed the DaemonSet, there is nothing to compare against
+	_, found, err := unstructured.NestedInt64(obj.Object, "status", "desiredNumberScheduled")
+	if err != nil {
+		return "", "", err
+	}
+	if !found {
+		return RolloutInProgress, "Missing .status.desiredNumberScheduled", nil
+	}
+
+	status, err := getInt64s(obj, "desiredNumberScheduled", "currentNumberScheduled", "updatedNumberScheduled", "numberAvailable", "numberReady")
+	if err != nil {
+		return "", "", err
+	}
+	desired, current, updated, available, ready := status[0], status[1], status[2], status[3], status[4]
+
+	switch {
+	case current < desired:
+		return RolloutInProgress, fmt.Sprintf("Scheduled: %d/%d", current, desired), nil
+	case updated < desired:
+		return RolloutInProgress, fmt.Sprintf("Updated: %d/%d", updated, desired), nil
+	case available < desired:
+		return RolloutInProgress, fmt.Sprintf("Available: %d/%d", available, desired), nil
+	case ready < desired:
+		return RolloutInProgress, fmt.Sprintf("Ready: %d/%d", ready, desired), nil
+	}
+	return RolloutCurrent, fmt.Sprintf("All replicas scheduled as expected. Replicas: %d", desired), nil
+}
+
+func replicaSetRollout(obj *unstructured.Unstructured) (RolloutStatus, string, error) {
+	failure, err := getCondition(obj, "ReplicaFailure")
+	if err != nil {
+		return "", "", err
+	}
+	if failure != nil && failure.status == "True" {
+		return RolloutInProgress, fmt.Sprintf("Replica failure: %s", failure.message), nil
+	}
+
+	replicas, err := specReplicas(obj)
+	if err != nil {
+		return "", "", err
+	}
+	status, err := getInt64s(obj, "replicas", "readyReplicas", "availableReplicas")
+	if err != nil {
+		return "", "", err
+	}
+	statusReplicas, ready, available := status[0], status[1], status[2]
+
+	switch {
+	case statusReplicas < replicas:
+		return RolloutInProgress, fmt.Sprintf("Replicas: %d/%d", statusReplicas, replicas), nil
+	case statusReplicas > replicas:
+		return RolloutInProgress, fmt.Sprintf("Pending termination: %d", statusReplicas-replicas), nil
+	case available < replicas:
+		return RolloutInProgress, fmt.Sprintf("Available: %d/%d", available, replicas), nil
+	case ready < replicas:
+		return RolloutInProgress, fmt.Sprintf("Ready: %d/%d", ready, replicas), nil
+	}
+	return RolloutCurrent, fmt.Sprintf("ReplicaSet is available. Replicas: %d", statusReplicas), nil
+}
+
+func jobRollout(obj *unstructured.Unstructured) (RolloutStatus, string, error) {
+	for _, conditionType := range []string{"Failed", "Complete"} {
+		c, err := getCondition(obj, conditionType)
+		if err != nil {
+			return "", "", err
+		}
+		if c == nil || c.status != "True" {
+			continue
+		}
+		if conditionType == "Failed" {
+			return RolloutFailed, fmt.Sprintf("Job failed: %s", c.message), nil
+		}
+		return RolloutCurrent, "Job completed", nil
+	}
+
+	status, err := getInt64s(obj, "active", "succeeded", "failed")
+	if err != nil {
+		return "", "", err
+	}
+	return RolloutInProgress, fmt.Sprintf("Job in progress. Active: %d, succeeded: %d, failed: %d", status[0], status[1], status[2]), nil
+}
+
+func pvcRollout(obj *unstructured.Unstructured) (RolloutStatus, string, error) {
+	phase, _, err := unstructured.NestedString(obj.Object, "status", "phase")
+	if err != nil {
+		return "", "", err
+	}
+	if phase != "Bound" {
+		return RolloutInProgress, "PersistentVolumeClaim is not Bound", nil
+	}
+	return RolloutCurrent, "PersistentVolumeClaim is Bound", nil
+}
+
+func serviceRollout(obj *unstructured.Unstructured) (RolloutStatus, string, error) {
+	serviceType, _, err := unstructured.NestedString(obj.Object, "spec", "type")
+	if err != nil {
+		return "", "", err
+	}
+	if serviceType != "LoadBalancer" {
+		return RolloutCurrent, "Service is ready", nil
+	}
+
+	ingress, _, err := unstructured.NestedSlice(obj.Object, "status", "loadBalancer", "ingress")
+	if err != nil {
+		return "", "", err
+	}
+	if len(ingress) == 0 {
+		return RolloutInProgress, "LoadBalancer ingress has not been assigned", nil
+	}
+	return RolloutCurrent, "Service is ready", nil
+}
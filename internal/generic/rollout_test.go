@@ -0,0 +1,163 @@
+package generic_test
+
+import (
+	"testing"
+
+	"github.com/kwohlfahrt/tf-k8s/internal/generic"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestRollout(t *testing.T) {
+	cases := []struct {
+		name     string
+		obj      string
+		expected generic.RolloutStatus
+	}{
+		{
+			name: "deployment not observed",
+			obj: `{"apiVersion": "apps/v1", "kind": "Deployment", "metadata": {"generation": 2},
+				"spec": {"replicas": 1},
+				"status": {"observedGeneration": 1, "replicas": 1, "updatedReplicas": 1, "readyReplicas": 1, "availableReplicas": 1}}`,
+			expected: generic.RolloutInProgress,
+		},
+		{
+			name: "deployment updating",
+			obj: `{"apiVersion": "apps/v1", "kind": "Deployment", "metadata": {"generation": 1},
+				"spec": {"replicas": 3},
+				"status": {"observedGeneration": 1, "replicas": 3, "updatedReplicas": 1, "readyReplicas": 3, "availableReplicas": 3}}`,
+			expected: generic.RolloutInProgress,
+		},
+		{
+			name: "deployment deadline exceeded",
+			obj: `{"apiVersion": "apps/v1", "kind": "Deployment", "metadata": {"generation": 1},
+				"spec": {"replicas": 1},
+				"status": {"observedGeneration": 1, "conditions": [{"type": "Progressing", "status": "False", "reason": "ProgressDeadlineExceeded"}]}}`,
+			expected: generic.RolloutFailed,
+		},
+		{
+			name: "deployment available",
+			obj: `{"apiVersion": "apps/v1", "kind": "Deployment", "metadata": {"generation": 1},
+				"spec": {"replicas": 2},
+				"status": {"observedGeneration": 1, "replicas": 2, "updatedReplicas": 2, "readyReplicas": 2, "availableReplicas": 2}}`,
+			expected: generic.RolloutCurrent,
+		},
+		{
+			name: "statefulset revision pending",
+			obj: `{"apiVersion": "apps/v1", "kind": "StatefulSet", "metadata": {"generation": 1},
+				"spec": {"replicas": 1},
+				"status": {"observedGeneration": 1, "replicas": 1, "readyReplicas": 1, "currentReplicas": 1, "updatedReplicas": 1,
+					"currentRevision": "a", "updateRevision": "b"}}`,
+			expected: generic.RolloutInProgress,
+		},
+		{
+			name: "statefulset partitioned",
+			obj: `{"apiVersion": "apps/v1", "kind": "StatefulSet", "metadata": {"generation": 1},
+				"spec": {"replicas": 3, "updateStrategy": {"type": "RollingUpdate", "rollingUpdate": {"partition": 2}}},
+				"status": {"observedGeneration": 1, "replicas": 3, "readyReplicas": 3, "currentReplicas": 2, "updatedReplicas": 1,
+					"currentRevision": "a", "updateRevision": "b"}}`,
+			expected: generic.RolloutCurrent,
+		},
+		{
+			name:     "daemonset without status",
+			obj:      `{"apiVersion": "apps/v1", "kind": "DaemonSet", "metadata": {"generation": 1}}`,
+			expected: generic.RolloutInProgress,
+		},
+		{
+			name: "daemonset unavailable",
+			obj: `{"apiVersion": "apps/v1", "kind": "DaemonSet", "metadata": {"generation": 1},
+				"status": {"observedGeneration": 1, "desiredNumberScheduled": 2, "currentNumberScheduled": 2,
+					"updatedNumberScheduled": 2, "numberAvailable": 1, "numberReady": 1}}`,
+			expected: generic.RolloutInProgress,
+		},
+		{
+			name: "replicaset ready",
+			obj: `{"apiVersion": "apps/v1", "kind": "ReplicaSet", "metadata": {"generation": 1},
+				"status": {"observedGeneration": 1, "replicas": 1, "readyReplicas": 1, "availableReplicas": 1}}`,
+			expected: generic.RolloutCurrent,
+		},
+		{
+			name: "job running",
+			obj: `{"apiVersion": "batch/v1", "kind": "Job", "metadata": {"generation": 1},
+				"status": {"active": 1}}`,
+			expected: generic.RolloutInProgress,
+		},
+		{
+			name: "job failed",
+			obj: `{"apiVersion": "batch/v1", "kind": "Job", "metadata": {"generation": 1},
+				"status": {"conditions": [{"type": "Failed", "status": "True", "message": "BackoffLimitExceeded"}]}}`,
+			expected: generic.RolloutFailed,
+		},
+		{
+			name: "job complete",
+			obj: `{"apiVersion": "batch/v1", "kind": "Job", "metadata": {"generation": 1},
+				"status": {"succeeded": 1, "conditions": [{"type": "Complete", "status": "True"}]}}`,
+			expected: generic.RolloutCurrent,
+		},
+		{
+			name:     "pvc pending",
+			obj:      `{"apiVersion": "v1", "kind": "PersistentVolumeClaim", "status": {"phase": "Pending"}}`,
+			expected: generic.RolloutInProgress,
+		},
+		{
+			name:     "pvc bound",
+			obj:      `{"apiVersion": "v1", "kind": "PersistentVolumeClaim", "status": {"phase": "Bound"}}`,
+			expected: generic.RolloutCurrent,
+		},
+		{
+			name:     "service cluster ip",
+			obj:      `{"apiVersion": "v1", "kind": "Service", "spec": {"type": "ClusterIP"}}`,
+			expected: generic.RolloutCurrent,
+		},
+		{
+			name:     "service load balancer pending",
+			obj:      `{"apiVersion": "v1", "kind": "Service", "spec": {"type": "LoadBalancer"}, "status": {"loadBalancer": {}}}`,
+			expected: generic.RolloutInProgress,
+		},
+		{
+			name: "service load balancer assigned",
+			obj: `{"apiVersion": "v1", "kind": "Service", "spec": {"type": "LoadBalancer"},
+				"status": {"loadBalancer": {"ingress": [{"ip": "192.0.2.1"}]}}}`,
+			expected: generic.RolloutCurrent,
+		},
+		{
+			name: "custom resource not ready",
+			obj: `{"apiVersion": "example.com/v1", "kind": "Foo", "metadata": {"generation": 1},
+				"status": {"conditions": [{"type": "Ready", "status": "False"}]}}`,
+			expected: generic.RolloutInProgress,
+		},
+		{
+			name: "custom resource stalled",
+			obj: `{"apiVersion": "example.com/v1", "kind": "Foo", "metadata": {"generation": 1},
+				"status": {"conditions": [{"type": "Stalled", "status": "True"}]}}`,
+			expected: generic.RolloutFailed,
+		},
+		{
+			name:     "custom resource without status",
+			obj:      `{"apiVersion": "example.com/v1", "kind": "Foo", "metadata": {"generation": 1}}`,
+			expected: generic.RolloutCurrent,
+		},
+		{
+			name:     "terminating",
+			obj:      `{"apiVersion": "v1", "kind": "Service", "metadata": {"deletionTimestamp": "2024-01-01T00:00:00Z"}}`,
+			expected: generic.RolloutTerminating,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var obj unstructured.Unstructured
+			// Decodes integers as int64, as the dynamic client does
+			if err := obj.UnmarshalJSON([]byte(c.obj)); err != nil {
+				t.Fatal(err)
+			}
+
+			status, message, err := generic.Rollout(&obj)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if status != c.expected {
+				t.Errorf("expected %s, got %s (%s)", c.expected, status, message)
+			}
+		})
+	}
+}
@@ -20,6 +20,22 @@ type TypeInfo struct {
 	Version    string
 	Namespaced bool
 	Schema     types.KubernetesObjectType
+	// The type of the `status` field, which is set by the server so is not part
+	// of `Schema`. Nil if the type has no status.
+	Status types.KubernetesType
+	// The `additionalPrinterColumns` of a CRD. Empty for built-in types.
+	PrinterColumns []PrinterColumn
+}
+
+// PrinterColumn is a column shown by `kubectl get`, see
+// https://kubernetes.io/docs/tasks/extend-kubernetes/custom-resources/custom-resource-definitions/#additional-printer-columns
+type PrinterColumn struct {
+	// The name of the attribute the column is exposed as
+	Attribute   string
+	Name        string
+	Type        string
+	Description string
+	JSONPath    string
 }
 
 func (t TypeInfo) GroupVersionResource() runtimeschema.GroupVersionResource {
@@ -30,6 +46,15 @@ func (t TypeInfo) GroupVersionResource() runtimeschema.GroupVersionResource {
 	}
 }
 
+// ObjectSchema returns the type of the full object, which unlike `Schema`
+// includes the status.
+func (t TypeInfo) ObjectSchema() types.KubernetesObjectType {
+	if t.Status == nil {
+		return t.Schema
+	}
+	return t.Schema.WithField("status", "status", t.Status)
+}
+
 func (t TypeInfo) Interface(client *dynamic.DynamicClient, namespace string) dynamic.ResourceInterface {
 	namespaceable := client.Resource(t.GroupVersionResource())
 	resource := namespaceable.(dynamic.ResourceInterface)
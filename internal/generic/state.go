@@ -3,6 +3,9 @@ package generic
 import (
 	"bytes"
 	"context"
+	"errors"
+	"fmt"
+	"slices"
 
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
@@ -11,6 +14,8 @@ import (
 	"github.com/kwohlfahrt/tf-k8s/internal/types"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/util/csaupgrade"
 	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
 )
 
@@ -84,3 +89,200 @@ func GetManagedFieldSet(in *unstructured.Unstructured, fieldManager string) (*fi
 	fieldSet = fieldSet.Union(&defaultFields)
 	return fieldSet, nil
 }
+
+// FieldOwners returns the field managers that own the given field of in.
+func FieldOwners(in *unstructured.Unstructured, field fieldpath.Path) ([]string, diag.Diagnostics) {
+	owners := make([]string, 0)
+	for _, entry := range in.GetManagedFields() {
+		if entry.FieldsV1 == nil {
+			continue
+		}
+		fieldSet := &fieldpath.Set{}
+		if err := fieldSet.FromJSON(bytes.NewReader(entry.FieldsV1.Raw)); err != nil {
+			return nil, diag.Diagnostics{diag.NewErrorDiagnostic("Unable to parse managed fields", err.Error())}
+		}
+		if fieldSet.Has(field) && !slices.Contains(owners, entry.Manager) {
+			owners = append(owners, entry.Manager)
+		}
+	}
+	return owners, nil
+}
+
+// RenameFieldManager returns a copy of entries, where the fields applied by
+// `from` are applied by `to` instead. If `to` has already applied fields with
+// the same API version, the two are merged.
+func RenameFieldManager(entries []v1.ManagedFieldsEntry, from string, to string) ([]v1.ManagedFieldsEntry, error) {
+	isApply := func(manager string) func(v1.ManagedFieldsEntry) bool {
+		return func(entry v1.ManagedFieldsEntry) bool {
+			return entry.Manager == manager && entry.Operation == v1.ManagedFieldsOperationApply && entry.Subresource == ""
+		}
+	}
+
+	result := slices.Clone(entries)
+	fromIdx := slices.IndexFunc(result, isApply(from))
+	if fromIdx < 0 || from == to {
+		return result, nil
+	}
+	toIdx := slices.IndexFunc(result, isApply(to))
+	if toIdx < 0 {
+		result[fromIdx].Manager = to
+		return result, nil
+	}
+
+	fromEntry, toEntry := result[fromIdx], result[toIdx]
+	if fromEntry.APIVersion != toEntry.APIVersion {
+		return nil, fmt.Errorf(
+			"field managers %q and %q have applied different API versions (%s and %s)",
+			from, to, fromEntry.APIVersion, toEntry.APIVersion,
+		)
+	}
+	fields := &fieldpath.Set{}
+	for _, entry := range []v1.ManagedFieldsEntry{fromEntry, toEntry} {
+		if entry.FieldsV1 == nil {
+			continue
+		}
+		entryFields := &fieldpath.Set{}
+		if err := entryFields.FromJSON(bytes.NewReader(entry.FieldsV1.Raw)); err != nil {
+			return nil, err
+		}
+		fields = fields.Union(entryFields)
+	}
+	raw, err := fields.ToJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	result[toIdx].FieldsV1 = &v1.FieldsV1{Raw: raw}
+	return slices.Delete(result, fromIdx, fromIdx+1), nil
+}
+
+// AdoptFieldManagers returns a copy of entries, where the fields owned by
+// `managers` are applied by `to` instead. Fields set with `operation: Update`
+// are migrated as by `csaupgrade`, fields applied with `operation: Apply` are
+// merged as by `RenameFieldManager`.
+func AdoptFieldManagers(entries []v1.ManagedFieldsEntry, managers sets.Set[string], to string) ([]v1.ManagedFieldsEntry, error) {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	obj.SetManagedFields(slices.Clone(entries))
+	if err := csaupgrade.UpgradeManagedFields(obj, managers, to); err != nil {
+		return nil, err
+	}
+
+	result := obj.GetManagedFields()
+	for _, manager := range sets.List(managers) {
+		var err error
+		result, err = RenameFieldManager(result, manager, to)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// ReleaseAppliedFields returns a copy of entries, where the fields applied by
+// `fieldManager` are converted to fields it set with `operation: Update`. The
+// values are kept, but are no longer removed when `fieldManager` stops
+// applying them. If it has also set fields with the same API version, the two
+// are merged.
+func ReleaseAppliedFields(entries []v1.ManagedFieldsEntry, fieldManager string) ([]v1.ManagedFieldsEntry, error) {
+	result := slices.Clone(entries)
+	applyIdx := slices.IndexFunc(result, func(entry v1.ManagedFieldsEntry) bool {
+		return entry.Manager == fieldManager && entry.Operation == v1.ManagedFieldsOperationApply && entry.Subresource == ""
+	})
+	if applyIdx < 0 {
+		return result, nil
+	}
+	applyEntry := result[applyIdx]
+	updateIdx := slices.IndexFunc(result, func(entry v1.ManagedFieldsEntry) bool {
+		return entry.Manager == fieldManager && entry.Operation == v1.ManagedFieldsOperationUpdate &&
+			entry.Subresource == "" && entry.APIVersion == applyEntry.APIVersion
+	})
+	if updateIdx < 0 {
+		result[applyIdx].Operation = v1.ManagedFieldsOperationUpdate
+		return result, nil
+	}
+
+	fields := &fieldpath.Set{}
+	for _, entry := range []v1.ManagedFieldsEntry{applyEntry, result[updateIdx]} {
+		if entry.FieldsV1 == nil {
+			continue
+		}
+		entryFields := &fieldpath.Set{}
+		if err := entryFields.FromJSON(bytes.NewReader(entry.FieldsV1.Raw)); err != nil {
+			return nil, err
+		}
+		fields = fields.Union(entryFields)
+	}
+	raw, err := fields.ToJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	result[updateIdx].FieldsV1 = &v1.FieldsV1{Raw: raw}
+	return slices.Delete(result, applyIdx, applyIdx+1), nil
+}
+
+// ErrUnmatchedFields is returned by `ApplyCreatedFields` if the server tracks
+// some of the applied fields differently, e.g. items of a list-map keyed by a
+// field that the server defaulted.
+var ErrUnmatchedFields = errors.New("applied fields do not match the fields set on creation")
+
+// untrackedFields are never recorded in managedFields by the server.
+var untrackedFields = fieldpath.NewSet(
+	fieldpath.MakePathOrDie("apiVersion"),
+	fieldpath.MakePathOrDie("kind"),
+	fieldpath.MakePathOrDie("metadata", "name"),
+	fieldpath.MakePathOrDie("metadata", "namespace"),
+	fieldpath.MakePathOrDie("metadata", "uid"),
+	fieldpath.MakePathOrDie("metadata", "resourceVersion"),
+	fieldpath.MakePathOrDie("metadata", "generation"),
+	fieldpath.MakePathOrDie("metadata", "creationTimestamp"),
+	fieldpath.MakePathOrDie("metadata", "managedFields"),
+	fieldpath.MakePathOrDie("metadata", "selfLink"),
+)
+
+// ApplyCreatedFields returns a copy of entries, where the fields set by
+// `fieldManager` when creating the object are converted to fields applied by
+// it. Only fields in `applied` (or their parents) are kept, so the result is
+// the same as server-side applying the fields after creating the object.
+//
+// If any of `applied` were not set on creation, the server tracks them
+// differently and the result would not match an apply, so
+// `ErrUnmatchedFields` is returned instead.
+func ApplyCreatedFields(entries []v1.ManagedFieldsEntry, fieldManager string, applied *fieldpath.Set) ([]v1.ManagedFieldsEntry, error) {
+	result := slices.Clone(entries)
+	idx := slices.IndexFunc(result, func(entry v1.ManagedFieldsEntry) bool {
+		return entry.Manager == fieldManager && entry.Operation == v1.ManagedFieldsOperationUpdate && entry.Subresource == ""
+	})
+	if idx < 0 || result[idx].FieldsV1 == nil {
+		return result, nil
+	}
+	if slices.ContainsFunc(result, func(entry v1.ManagedFieldsEntry) bool {
+		return entry.Manager == fieldManager && entry.Operation == v1.ManagedFieldsOperationApply && entry.Subresource == ""
+	}) {
+		return nil, fmt.Errorf("field manager %q has already applied fields", fieldManager)
+	}
+
+	created := &fieldpath.Set{}
+	if err := created.FromJSON(bytes.NewReader(result[idx].FieldsV1.Raw)); err != nil {
+		return nil, err
+	}
+	applied = applied.RecursiveDifference(untrackedFields)
+	if !applied.Leaves().Difference(created).Empty() {
+		return nil, ErrUnmatchedFields
+	}
+	// The server also tracks maps and list items that contain applied fields
+	withParents := &fieldpath.Set{}
+	applied.Iterate(func(p fieldpath.Path) {
+		for i := 1; i <= len(p); i++ {
+			withParents.Insert(p[:i].Copy())
+		}
+	})
+	raw, err := created.Intersection(withParents).ToJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	result[idx].Operation = v1.ManagedFieldsOperationApply
+	result[idx].FieldsV1 = &v1.FieldsV1{Raw: raw}
+	return result, nil
+}
@@ -0,0 +1,328 @@
+package generic_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/kwohlfahrt/tf-k8s/internal/generic"
+	"github.com/kwohlfahrt/tf-k8s/internal/types"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
+)
+
+// managedFieldsEntry builds a managedFields entry, with fields in the FieldsV1
+// JSON format.
+func managedFieldsEntry(manager string, operation v1.ManagedFieldsOperationType, apiVersion string, fields string) v1.ManagedFieldsEntry {
+	return v1.ManagedFieldsEntry{
+		Manager:    manager,
+		Operation:  operation,
+		APIVersion: apiVersion,
+		FieldsType: "FieldsV1",
+		FieldsV1:   &v1.FieldsV1{Raw: []byte(fields)},
+	}
+}
+
+func TestRenameFieldManager(t *testing.T) {
+	cases := []struct {
+		name     string
+		entries  []v1.ManagedFieldsEntry
+		expected []v1.ManagedFieldsEntry
+	}{
+		{
+			name: "rename",
+			entries: []v1.ManagedFieldsEntry{
+				managedFieldsEntry("old", v1.ManagedFieldsOperationApply, "v1", `{"f:spec":{"f:foo":{}}}`),
+				managedFieldsEntry("old", v1.ManagedFieldsOperationUpdate, "v1", `{"f:status":{}}`),
+			},
+			expected: []v1.ManagedFieldsEntry{
+				managedFieldsEntry("new", v1.ManagedFieldsOperationApply, "v1", `{"f:spec":{"f:foo":{}}}`),
+				managedFieldsEntry("old", v1.ManagedFieldsOperationUpdate, "v1", `{"f:status":{}}`),
+			},
+		},
+		{
+			name: "merge",
+			entries: []v1.ManagedFieldsEntry{
+				managedFieldsEntry("new", v1.ManagedFieldsOperationApply, "v1", `{"f:spec":{"f:bar":{}}}`),
+				managedFieldsEntry("old", v1.ManagedFieldsOperationApply, "v1", `{"f:spec":{"f:foo":{}}}`),
+			},
+			expected: []v1.ManagedFieldsEntry{
+				managedFieldsEntry("new", v1.ManagedFieldsOperationApply, "v1", `{"f:spec":{"f:bar":{},"f:foo":{}}}`),
+			},
+		},
+		{
+			name: "missing",
+			entries: []v1.ManagedFieldsEntry{
+				managedFieldsEntry("other", v1.ManagedFieldsOperationApply, "v1", `{"f:spec":{"f:foo":{}}}`),
+			},
+			expected: []v1.ManagedFieldsEntry{
+				managedFieldsEntry("other", v1.ManagedFieldsOperationApply, "v1", `{"f:spec":{"f:foo":{}}}`),
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			result, err := generic.RenameFieldManager(c.entries, "old", "new")
+			if err != nil {
+				t.Fatal(err)
+			}
+			actual, err := json.Marshal(result)
+			if err != nil {
+				t.Fatal(err)
+			}
+			expected, err := json.Marshal(c.expected)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(actual) != string(expected) {
+				t.Errorf("expected %s, got %s", expected, actual)
+			}
+		})
+	}
+
+	t.Run("different api versions", func(t *testing.T) {
+		entries := []v1.ManagedFieldsEntry{
+			managedFieldsEntry("new", v1.ManagedFieldsOperationApply, "v1", `{"f:spec":{"f:bar":{}}}`),
+			managedFieldsEntry("old", v1.ManagedFieldsOperationApply, "v2", `{"f:spec":{"f:foo":{}}}`),
+		}
+		if _, err := generic.RenameFieldManager(entries, "old", "new"); err == nil {
+			t.Error("expected an error")
+		}
+	})
+}
+
+func TestAdoptFieldManagers(t *testing.T) {
+	entries := []v1.ManagedFieldsEntry{
+		managedFieldsEntry("ours", v1.ManagedFieldsOperationApply, "v1", `{"f:data":{"f:foo":{}}}`),
+		managedFieldsEntry("kubectl-client-side-apply", v1.ManagedFieldsOperationUpdate, "v1", `{"f:data":{"f:bar":{}}}`),
+		managedFieldsEntry("helm", v1.ManagedFieldsOperationApply, "v1", `{"f:data":{"f:baz":{}}}`),
+		managedFieldsEntry("other", v1.ManagedFieldsOperationApply, "v1", `{"f:data":{"f:qux":{}}}`),
+	}
+
+	result, err := generic.AdoptFieldManagers(entries, sets.New("kubectl-client-side-apply", "helm"), "ours")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		"ours":  `{"f:data":{"f:bar":{},"f:baz":{},"f:foo":{}}}`,
+		"other": `{"f:data":{"f:qux":{}}}`,
+	}
+	if len(result) != len(expected) {
+		t.Fatalf("expected entries for %v, got %v", expected, result)
+	}
+	for _, entry := range result {
+		if entry.Operation != v1.ManagedFieldsOperationApply {
+			t.Errorf("expected %s to apply fields, got %s", entry.Manager, entry.Operation)
+		}
+		fields := &fieldpath.Set{}
+		if err := fields.FromJSON(bytes.NewReader(entry.FieldsV1.Raw)); err != nil {
+			t.Fatal(err)
+		}
+		expectedFields := &fieldpath.Set{}
+		if err := expectedFields.FromJSON(strings.NewReader(expected[entry.Manager])); err != nil {
+			t.Fatal(err)
+		}
+		if !fields.Equals(expectedFields) {
+			t.Errorf("expected %s to own %s, got %s", entry.Manager, expectedFields, fields)
+		}
+	}
+}
+
+func TestAdoptFieldManagersRename(t *testing.T) {
+	// The old field manager set `bar` without applying it, e.g. when creating
+	// the object
+	entries := []v1.ManagedFieldsEntry{
+		managedFieldsEntry("old", v1.ManagedFieldsOperationApply, "v1", `{"f:data":{"f:foo":{}}}`),
+		managedFieldsEntry("old", v1.ManagedFieldsOperationUpdate, "v1", `{"f:data":{"f:bar":{}}}`),
+	}
+
+	result, err := generic.AdoptFieldManagers(entries, sets.New("old"), "new")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result) != 1 || result[0].Manager != "new" || result[0].Operation != v1.ManagedFieldsOperationApply {
+		t.Fatalf("expected a single entry applied by new, got %v", result)
+	}
+	fields := &fieldpath.Set{}
+	if err := fields.FromJSON(bytes.NewReader(result[0].FieldsV1.Raw)); err != nil {
+		t.Fatal(err)
+	}
+	expected := fieldpath.NewSet(fieldpath.MakePathOrDie("data", "foo"), fieldpath.MakePathOrDie("data", "bar"))
+	if !fields.Leaves().Equals(expected) {
+		t.Errorf("expected %s, got %s", expected, fields)
+	}
+}
+
+func TestReleaseAppliedFields(t *testing.T) {
+	cases := []struct {
+		name     string
+		entries  []v1.ManagedFieldsEntry
+		expected []v1.ManagedFieldsEntry
+	}{
+		{
+			name: "release",
+			entries: []v1.ManagedFieldsEntry{
+				managedFieldsEntry("ours", v1.ManagedFieldsOperationApply, "v1", `{"f:spec":{"f:foo":{}}}`),
+				managedFieldsEntry("other", v1.ManagedFieldsOperationApply, "v1", `{"f:spec":{"f:bar":{}}}`),
+			},
+			expected: []v1.ManagedFieldsEntry{
+				managedFieldsEntry("ours", v1.ManagedFieldsOperationUpdate, "v1", `{"f:spec":{"f:foo":{}}}`),
+				managedFieldsEntry("other", v1.ManagedFieldsOperationApply, "v1", `{"f:spec":{"f:bar":{}}}`),
+			},
+		},
+		{
+			name: "merge",
+			entries: []v1.ManagedFieldsEntry{
+				managedFieldsEntry("ours", v1.ManagedFieldsOperationUpdate, "v1", `{"f:spec":{"f:bar":{}}}`),
+				managedFieldsEntry("ours", v1.ManagedFieldsOperationApply, "v1", `{"f:spec":{"f:foo":{}}}`),
+			},
+			expected: []v1.ManagedFieldsEntry{
+				managedFieldsEntry("ours", v1.ManagedFieldsOperationUpdate, "v1", `{"f:spec":{"f:bar":{},"f:foo":{}}}`),
+			},
+		},
+		{
+			name: "different api versions",
+			entries: []v1.ManagedFieldsEntry{
+				managedFieldsEntry("ours", v1.ManagedFieldsOperationUpdate, "v1", `{"f:spec":{"f:bar":{}}}`),
+				managedFieldsEntry("ours", v1.ManagedFieldsOperationApply, "v2", `{"f:spec":{"f:foo":{}}}`),
+			},
+			expected: []v1.ManagedFieldsEntry{
+				managedFieldsEntry("ours", v1.ManagedFieldsOperationUpdate, "v1", `{"f:spec":{"f:bar":{}}}`),
+				managedFieldsEntry("ours", v1.ManagedFieldsOperationUpdate, "v2", `{"f:spec":{"f:foo":{}}}`),
+			},
+		},
+		{
+			name: "missing",
+			entries: []v1.ManagedFieldsEntry{
+				managedFieldsEntry("other", v1.ManagedFieldsOperationApply, "v1", `{"f:spec":{"f:foo":{}}}`),
+			},
+			expected: []v1.ManagedFieldsEntry{
+				managedFieldsEntry("other", v1.ManagedFieldsOperationApply, "v1", `{"f:spec":{"f:foo":{}}}`),
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			result, err := generic.ReleaseAppliedFields(c.entries, "ours")
+			if err != nil {
+				t.Fatal(err)
+			}
+			actual, err := json.Marshal(result)
+			if err != nil {
+				t.Fatal(err)
+			}
+			expected, err := json.Marshal(c.expected)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(actual) != string(expected) {
+				t.Errorf("expected %s, got %s", expected, actual)
+			}
+		})
+	}
+}
+
+func TestApplyCreatedFields(t *testing.T) {
+	created := managedFieldsEntry(
+		"manager", v1.ManagedFieldsOperationUpdate, "v1",
+		`{"f:metadata":{"f:labels":{".":{},"f:foo":{}}},"f:spec":{"f:default":{},"f:foo":{}}}`,
+	)
+	other := managedFieldsEntry("other", v1.ManagedFieldsOperationUpdate, "v1", `{"f:status":{}}`)
+
+	applied := fieldpath.NewSet(
+		fieldpath.MakePathOrDie("metadata", "name"),
+		fieldpath.MakePathOrDie("metadata", "labels", "foo"),
+		fieldpath.MakePathOrDie("spec", "foo"),
+	)
+	result, err := generic.ApplyCreatedFields([]v1.ManagedFieldsEntry{created, other}, "manager", applied)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := created
+	expected.Operation = v1.ManagedFieldsOperationApply
+	expected.FieldsV1 = &v1.FieldsV1{Raw: []byte(`{"f:metadata":{"f:labels":{".":{},"f:foo":{}}},"f:spec":{"f:foo":{}}}`)}
+	actualJSON, err := json.Marshal(result)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedJSON, err := json.Marshal([]v1.ManagedFieldsEntry{expected, other})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(actualJSON) != string(expectedJSON) {
+		t.Errorf("expected %s, got %s", expectedJSON, actualJSON)
+	}
+}
+
+func TestApplyCreatedFieldsDefaultedKey(t *testing.T) {
+	ctx := context.Background()
+	typ := objectTypeFromJSON(t, `{
+		"type": "object",
+		"properties": {
+			"spec": {
+				"type": "object",
+				"properties": {
+					"ports": {
+						"type": "array",
+						"x-kubernetes-list-type": "map",
+						"x-kubernetes-list-map-keys": ["containerPort", "protocol"],
+						"items": {
+							"type": "object",
+							"properties": {"containerPort": {"type": "integer"}, "protocol": {"type": "string"}}
+						}
+					}
+				}
+			}
+		}
+	}`)
+	// `protocol` is defaulted by the server, so is part of the key it tracks
+	value, diags := typ.ValueFromUnstructured(ctx, path.Empty(), nil, map[string]interface{}{
+		"spec": map[string]interface{}{"ports": []interface{}{map[string]interface{}{"containerPort": int64(80)}}},
+	})
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	applied := &fieldpath.Set{}
+	if diags := value.(types.KubernetesObjectValue).ManagedFields(ctx, path.Empty(), applied, nil); diags.HasError() {
+		t.Fatal(diags)
+	}
+
+	created := managedFieldsEntry(
+		"manager", v1.ManagedFieldsOperationUpdate, "v1",
+		`{"f:spec":{"f:ports":{"k:{\"containerPort\":80,\"protocol\":\"TCP\"}":{".":{},"f:containerPort":{},"f:protocol":{}}}}}`,
+	)
+	_, err := generic.ApplyCreatedFields([]v1.ManagedFieldsEntry{created}, "manager", applied)
+	if !errors.Is(err, generic.ErrUnmatchedFields) {
+		t.Errorf("expected %v, got %v", generic.ErrUnmatchedFields, err)
+	}
+
+	// With the key set explicitly, the fields match
+	value, diags = typ.ValueFromUnstructured(ctx, path.Empty(), nil, map[string]interface{}{
+		"spec": map[string]interface{}{"ports": []interface{}{
+			map[string]interface{}{"containerPort": int64(80), "protocol": "TCP"},
+		}},
+	})
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	applied = &fieldpath.Set{}
+	if diags := value.(types.KubernetesObjectValue).ManagedFields(ctx, path.Empty(), applied, nil); diags.HasError() {
+		t.Fatal(diags)
+	}
+	result, err := generic.ApplyCreatedFields([]v1.ManagedFieldsEntry{created}, "manager", applied)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result[0].Operation != v1.ManagedFieldsOperationApply {
+		t.Errorf("expected fields to be applied, got %s", result[0].Operation)
+	}
+}
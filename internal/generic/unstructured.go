@@ -2,9 +2,11 @@ package generic
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
 	"github.com/kwohlfahrt/tf-k8s/internal/types"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
@@ -28,7 +30,15 @@ func UnstructuredToValue(
 	return diags
 }
 
-func ValueToUnstructured(ctx context.Context, objectValue types.KubernetesValue, typeInfo TypeInfo) (*unstructured.Unstructured, diag.Diagnostics) {
+// ValueToUnstructured converts objectValue to an object to apply. The fields in
+// `ignored` are left to other field managers, so are removed even if they are
+// set. It may be nil if no fields are ignored.
+func ValueToUnstructured(
+	ctx context.Context,
+	objectValue types.KubernetesValue,
+	typeInfo TypeInfo,
+	ignored *fieldpath.Set,
+) (*unstructured.Unstructured, diag.Diagnostics) {
 	var diags diag.Diagnostics
 
 	obj, objDiags := objectValue.ToUnstructured(ctx, path.Empty())
@@ -38,8 +48,82 @@ func ValueToUnstructured(ctx context.Context, objectValue types.KubernetesValue,
 	}
 
 	objMap := obj.(map[string]interface{})
+	if ignored != nil {
+		RemoveFields(objMap, ignored)
+	}
 	objMap["kind"] = typeInfo.Kind
 	objMap["apiVersion"] = typeInfo.GroupVersionResource().GroupVersion().String()
 
 	return &unstructured.Unstructured{Object: objMap}, diags
 }
+
+// StatusToValue converts the `status` field of obj, which is not part of the
+// manifest, to a value of `typeInfo.Status`.
+func StatusToValue(ctx context.Context, typeInfo TypeInfo, obj unstructured.Unstructured) (basetypes.DynamicValue, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	value, valueDiags := typeInfo.Status.ValueFromUnstructured(ctx, path.Root("status"), nil, obj.Object["status"])
+	diags.Append(valueDiags...)
+	if diags.HasError() {
+		return basetypes.NewDynamicNull(), diags
+	}
+
+	dynamicValue, ok := value.(basetypes.DynamicValuable)
+	if !ok {
+		diags.AddAttributeError(path.Root("status"), "Unexpected value type", fmt.Sprintf("Expected dynamic value, got %T", value))
+		return basetypes.NewDynamicNull(), diags
+	}
+	result, valueDiags := dynamicValue.ToDynamicValue(ctx)
+	diags.Append(valueDiags...)
+	return result, diags
+}
+
+// fieldNames returns the names in `field`, or false if it has elements other
+// than field names (i.e. list items). Map keys are also field names.
+func fieldNames(field fieldpath.Path) ([]string, bool) {
+	names := make([]string, 0, len(field))
+	for _, element := range field {
+		if element.FieldName == nil {
+			return nil, false
+		}
+		names = append(names, *element.FieldName)
+	}
+	return names, len(names) > 0
+}
+
+// RemoveFields removes each of `fields` from obj. Only fields nested in
+// objects and maps are supported, others (in lists) are left as-is.
+func RemoveFields(obj map[string]interface{}, fields *fieldpath.Set) {
+	fields.Iterate(func(field fieldpath.Path) {
+		if names, ok := fieldNames(field); ok {
+			unstructured.RemoveNestedField(obj, names...)
+		}
+	})
+}
+
+// CopyFields sets each of `fields` in obj to its value in `from`, if it is set
+// there. Like `RemoveFields`, only fields nested in objects and maps are
+// supported.
+func CopyFields(obj map[string]interface{}, from map[string]interface{}, fields *fieldpath.Set) {
+	fields.Iterate(func(field fieldpath.Path) {
+		names, ok := fieldNames(field)
+		if !ok {
+			return
+		}
+		value, found, err := unstructured.NestedFieldNoCopy(from, names...)
+		if !found || err != nil {
+			return
+		}
+
+		parent := obj
+		for _, name := range names[:len(names)-1] {
+			child, ok := parent[name].(map[string]interface{})
+			if !ok {
+				child = make(map[string]interface{})
+				parent[name] = child
+			}
+			parent = child
+		}
+		parent[names[len(names)-1]] = value
+	})
+}
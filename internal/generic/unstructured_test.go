@@ -0,0 +1,116 @@
+package generic_test
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/kwohlfahrt/tf-k8s/internal/generic"
+	"github.com/kwohlfahrt/tf-k8s/internal/types"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
+)
+
+func objectTypeFromJSON(t *testing.T, rawSchema string) types.KubernetesObjectType {
+	var schema spec.Schema
+	if err := json.Unmarshal([]byte(rawSchema), &schema); err != nil {
+		t.Fatal(err)
+	}
+	typ, err := types.OpenApiToTfType(nil, schema, []string{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	objectTyp, ok := typ.(types.KubernetesObjectType)
+	if !ok {
+		t.Fatalf("expected KubernetesObjectType, got %T", typ)
+	}
+	return objectTyp
+}
+
+func TestValueToUnstructuredIgnored(t *testing.T) {
+	ctx := context.Background()
+	typ := objectTypeFromJSON(t, `{
+		"type": "object",
+		"properties": {
+			"metadata": {
+				"type": "object",
+				"properties": {
+					"name": {"type": "string"},
+					"annotations": {"type": "object", "additionalProperties": {"type": "string"}}
+				}
+			},
+			"spec": {
+				"type": "object",
+				"properties": {"replicas": {"type": "integer"}, "image": {"type": "string"}}
+			}
+		}
+	}`)
+	typeInfo := generic.TypeInfo{Kind: "Foo", Group: "example.com", Version: "v1", Schema: typ}
+
+	value, diags := typeInfo.Schema.ValueFromUnstructured(ctx, path.Empty(), nil, map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"name":        "foo",
+			"annotations": map[string]interface{}{"example.com/foo": "foo", "example.com/bar": "bar"},
+		},
+		"spec": map[string]interface{}{"replicas": int64(3), "image": "busybox"},
+	})
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+
+	ignored := &fieldpath.Set{}
+	for _, field := range []string{"spec.replicas", `metadata.annotations["example.com/foo"]`} {
+		fieldPath, err := typeInfo.Schema.FieldPath(field)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ignored.Insert(fieldPath)
+	}
+
+	obj, diags := generic.ValueToUnstructured(ctx, value.(types.KubernetesObjectValue), typeInfo, ignored)
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	expected := map[string]interface{}{
+		"apiVersion": "example.com/v1",
+		"kind":       "Foo",
+		"metadata": map[string]interface{}{
+			"name":        "foo",
+			"annotations": map[string]interface{}{"example.com/bar": "bar"},
+		},
+		"spec": map[string]interface{}{"image": "busybox"},
+	}
+	if !reflect.DeepEqual(obj.Object, expected) {
+		t.Errorf("expected %v, got %v", expected, obj.Object)
+	}
+}
+
+func TestCopyFields(t *testing.T) {
+	fields := fieldpath.NewSet(
+		fieldpath.MakePathOrDie("spec", "replicas"),
+		fieldpath.MakePathOrDie("metadata", "annotations", "example.com/foo"),
+		fieldpath.MakePathOrDie("spec", "missing"),
+	)
+	obj := map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "foo"},
+		"spec":     map[string]interface{}{"replicas": int64(1), "image": "busybox"},
+	}
+	from := map[string]interface{}{
+		"metadata": map[string]interface{}{"annotations": map[string]interface{}{"example.com/foo": "foo"}},
+		"spec":     map[string]interface{}{"replicas": int64(3)},
+	}
+
+	generic.CopyFields(obj, from, fields)
+	expected := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"name":        "foo",
+			"annotations": map[string]interface{}{"example.com/foo": "foo"},
+		},
+		"spec": map[string]interface{}{"replicas": int64(3), "image": "busybox"},
+	}
+	if !reflect.DeepEqual(obj, expected) {
+		t.Errorf("expected %v, got %v", expected, obj)
+	}
+}
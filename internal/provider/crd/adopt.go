@@ -0,0 +1,207 @@
+package crd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/kwohlfahrt/tf-k8s/internal/generic"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/util/retry"
+)
+
+func adoptFromManagersSchema() schema.Attribute {
+	return schema.SetAttribute{
+		MarkdownDescription: "Field managers to take ownership from before applying, e.g. `kubectl-client-side-apply` " +
+			"or `helm`. Fields they own are transferred to `field_manager`, so `manifest` " +
+			"can be applied without conflicts. As with any field owned by `field_manager`, transferred fields that " +
+			"are not in `manifest` are removed by the apply",
+		ElementType: basetypes.StringType{},
+		Optional:    true,
+	}
+}
+
+// adoptedManagers returns the managers listed in `adopt_from_managers`. If
+// these are not yet known, `known` is false and the set is empty.
+func adoptedManagers(ctx context.Context, config generic.PlanOrState) (managers sets.Set[string], known bool, diags diag.Diagnostics) {
+	managers = sets.New[string]()
+
+	var value basetypes.SetValue
+	diags.Append(config.GetAttribute(ctx, path.Root("adopt_from_managers"), &value)...)
+	if diags.HasError() || value.IsNull() {
+		return managers, true, diags
+	}
+	if value.IsUnknown() {
+		return managers, false, diags
+	}
+
+	var elements []basetypes.StringValue
+	diags.Append(value.ElementsAs(ctx, &elements, false)...)
+	for _, element := range elements {
+		if element.IsUnknown() {
+			return sets.New[string](), false, diags
+		}
+		managers.Insert(element.ValueString())
+	}
+	return managers, true, diags
+}
+
+// adoptManagedFields moves the fields owned by `managers`, whether set with
+// `operation: Update` or `operation: Apply`, to `fieldManager` with
+// `operation: Apply`.
+func adoptManagedFields(
+	ctx context.Context,
+	iface dynamic.ResourceInterface,
+	obj *unstructured.Unstructured,
+	managers sets.Set[string],
+	fieldManager string,
+) (*unstructured.Unstructured, error) {
+	return patchManagedFields(ctx, iface, obj, func(entries []metav1.ManagedFieldsEntry) ([]metav1.ManagedFieldsEntry, error) {
+		return generic.AdoptFieldManagers(entries, managers, fieldManager)
+	})
+}
+
+// renameFieldManager moves the fields owned by `from` to `to`, in a single
+// patch. As with `adoptManagedFields`, this includes fields `from` set without
+// applying them (e.g. when creating the object), which would otherwise conflict
+// with `to`.
+func renameFieldManager(
+	ctx context.Context,
+	iface dynamic.ResourceInterface,
+	obj *unstructured.Unstructured,
+	from string,
+	to string,
+) (*unstructured.Unstructured, error) {
+	return adoptManagedFields(ctx, iface, obj, sets.New(from), to)
+}
+
+// patchManagedFields replaces the managed fields of obj with the result of
+// `update`. The patch is guarded by the object's resourceVersion, so fields are
+// never transferred based on a stale read, and retried if the object is
+// modified concurrently. It fails if the object is re-created in the meantime.
+func patchManagedFields(
+	ctx context.Context,
+	iface dynamic.ResourceInterface,
+	obj *unstructured.Unstructured,
+	update func([]metav1.ManagedFieldsEntry) ([]metav1.ManagedFieldsEntry, error),
+) (*unstructured.Unstructured, error) {
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		managedFields, err := update(obj.GetManagedFields())
+		if err != nil {
+			return err
+		}
+		if reflect.DeepEqual(managedFields, obj.GetManagedFields()) {
+			return nil
+		}
+
+		patchData, err := json.Marshal([]map[string]any{
+			{"op": "replace", "path": "/metadata/managedFields", "value": managedFields},
+			// Conflicts if the object has been modified since it was read
+			{"op": "replace", "path": "/metadata/resourceVersion", "value": obj.GetResourceVersion()},
+		})
+		if err != nil {
+			return err
+		}
+
+		newObj, err := iface.Patch(ctx, obj.GetName(), k8stypes.JSONPatchType, patchData, metav1.PatchOptions{})
+		if err != nil && errors.IsConflict(err) {
+			newObj, getErr := iface.Get(ctx, obj.GetName(), metav1.GetOptions{})
+			if getErr != nil {
+				return getErr
+			}
+			if newObj.GetUID() != obj.GetUID() {
+				return fmt.Errorf("%s was re-created while updating its managed fields", obj.GetName())
+			}
+			obj = newObj
+		} else if err == nil {
+			obj = newObj
+		}
+		return err
+	})
+	return obj, err
+}
+
+type adoptExistingModel struct {
+	Labels      map[string]string `tfsdk:"labels"`
+	Annotations map[string]string `tfsdk:"annotations"`
+}
+
+func adoptExistingSchema() schema.Attribute {
+	return schema.SingleNestedAttribute{
+		MarkdownDescription: "Adopt the object if it already exists when the resource is created, instead of failing. " +
+			"The existing object is only adopted if it has all of the given labels and annotations",
+		Optional: true,
+		Attributes: map[string]schema.Attribute{
+			"labels": schema.MapAttribute{
+				MarkdownDescription: "Labels the existing object must have to be adopted",
+				ElementType:         basetypes.StringType{},
+				Optional:            true,
+			},
+			"annotations": schema.MapAttribute{
+				MarkdownDescription: "Annotations the existing object must have to be adopted",
+				ElementType:         basetypes.StringType{},
+				Optional:            true,
+			},
+		},
+	}
+}
+
+// adoptExisting returns the `adopt_existing` configuration, or nil if it is
+// not set. If it is not yet known, `known` is false.
+func adoptExisting(ctx context.Context, config generic.PlanOrState) (adopt *adoptExistingModel, known bool, diags diag.Diagnostics) {
+	var value basetypes.ObjectValue
+	diags.Append(config.GetAttribute(ctx, path.Root("adopt_existing"), &value)...)
+	if diags.HasError() {
+		return nil, false, diags
+	}
+	tfValue, err := value.ToTerraformValue(ctx)
+	if err != nil {
+		diags.AddAttributeError(path.Root("adopt_existing"), "Unable to read adopt_existing", err.Error())
+		return nil, false, diags
+	}
+	if !tfValue.IsFullyKnown() {
+		return nil, false, diags
+	}
+	if value.IsNull() {
+		return nil, true, diags
+	}
+
+	adopt = &adoptExistingModel{}
+	diags.Append(value.As(ctx, adopt, basetypes.ObjectAsOptions{})...)
+	return adopt, true, diags
+}
+
+// checkAdoptable reports an error if the existing object may not be adopted.
+func checkAdoptable(existing *unstructured.Unstructured, adopt adoptExistingModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if existing.GetDeletionTimestamp() != nil {
+		diags.AddError("Unable to adopt resource", fmt.Sprintf("The existing %s is being deleted", existing.GetKind()))
+		return diags
+	}
+
+	check := func(name string, attr path.Path, expected, actual map[string]string) {
+		for k, v := range expected {
+			if actualValue, found := actual[k]; !found || actualValue != v {
+				diags.AddAttributeError(
+					attr.AtMapKey(k),
+					"Unable to adopt resource",
+					fmt.Sprintf("The existing %s does not have the %s %s=%q", existing.GetKind(), name, k, v),
+				)
+			}
+		}
+	}
+	check("label", path.Root("adopt_existing").AtName("labels"), adopt.Labels, existing.GetLabels())
+	check("annotation", path.Root("adopt_existing").AtName("annotations"), adopt.Annotations, existing.GetAnnotations())
+	return diags
+}
@@ -0,0 +1,216 @@
+package crd
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/kwohlfahrt/tf-k8s/internal/generic"
+	"github.com/kwohlfahrt/tf-k8s/internal/types"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
+)
+
+var conflictManager = regexp.MustCompile(`^conflict with "([^"]*)"`)
+
+// applyErrorDiagnostics converts an error from a server-side apply into
+// diagnostics. Conflicts are reported against the conflicting attribute of
+// `manifest`, other errors are reported as-is.
+func applyErrorDiagnostics(typeInfo generic.TypeInfo, summary string, err error) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	status, ok := err.(errors.APIStatus)
+	if !ok || !errors.IsConflict(err) || status.Status().Details == nil {
+		diags.AddError(summary, err.Error())
+		return diags
+	}
+
+	for _, cause := range status.Status().Details.Causes {
+		if cause.Type != metav1.CauseTypeFieldManagerConflict {
+			continue
+		}
+
+		manager := "another field manager"
+		if match := conflictManager.FindStringSubmatch(cause.Message); match != nil {
+			manager = fmt.Sprintf("the field manager %q", match[1])
+		}
+		diags.AddAttributeError(
+			typeInfo.Schema.AttributePath(path.Root("manifest"), cause.Field),
+			summary,
+			fmt.Sprintf(
+				"The field %s is owned by %s. Set `force_conflicts = true` to take ownership of it, "+
+					"or remove it from `manifest` (and add it to `ignore_fields`) to leave it to the other manager.",
+				cause.Field, manager,
+			),
+		)
+	}
+
+	if !diags.HasError() {
+		diags.AddError(summary, err.Error())
+	}
+	return diags
+}
+
+// conflictsOnlyWith returns whether err is an apply conflict, where all of the
+// conflicting fields are owned by one of `managers`.
+func conflictsOnlyWith(err error, managers sets.Set[string]) bool {
+	status, ok := err.(errors.APIStatus)
+	if !ok || !errors.IsConflict(err) || status.Status().Details == nil {
+		return false
+	}
+
+	found := false
+	for _, cause := range status.Status().Details.Causes {
+		if cause.Type != metav1.CauseTypeFieldManagerConflict {
+			continue
+		}
+		match := conflictManager.FindStringSubmatch(cause.Message)
+		if match == nil || !managers.Has(match[1]) {
+			return false
+		}
+		found = true
+	}
+	return found
+}
+
+// otherWorkspaceDiagnostics warns if obj is also applied by the default field
+// manager of another workspace (see the provider's `workspace`), which would
+// otherwise silently share ownership of the object with us.
+func otherWorkspaceDiagnostics(obj *unstructured.Unstructured, fieldManager string) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	for _, entry := range obj.GetManagedFields() {
+		if entry.Operation != metav1.ManagedFieldsOperationApply || entry.Manager == fieldManager {
+			continue
+		}
+		if entry.Manager != defaultFieldManager && !strings.HasPrefix(entry.Manager, defaultFieldManager+"-") {
+			continue
+		}
+		diags.AddWarning(
+			"Object managed by another workspace",
+			fmt.Sprintf(
+				"The %s %s is also managed by the field manager %q, which may be another Terraform workspace. "+
+					"Both will apply their configuration to it. Set the provider's `workspace` to tell workspaces apart.",
+				obj.GetKind(), obj.GetName(), entry.Manager,
+			),
+		)
+	}
+	return diags
+}
+
+// lostOwnership returns the fields that we owned as of the last read
+// (`prior`), but have since been taken over by other field managers, with
+// their new owners.
+func lostOwnership(prior *fieldpath.Set, owned *fieldpath.Set, obj *unstructured.Unstructured) (map[string][]string, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	lost := make(map[string][]string)
+
+	prior.Difference(owned).Leaves().Iterate(func(field fieldpath.Path) {
+		owners, ownerDiags := generic.FieldOwners(obj, field)
+		diags.Append(ownerDiags...)
+		if ownerDiags.HasError() {
+			return
+		}
+		// The field was removed, rather than taken over
+		if len(owners) == 0 {
+			return
+		}
+		lost[field.String()] = owners
+	})
+	return lost, diags
+}
+
+// stillApplied filters the fields returned by `lostOwnership` to those in
+// `applied`.
+func stillApplied(lost map[string][]string, applied *fieldpath.Set) map[string][]string {
+	result := make(map[string][]string)
+	applied.Leaves().Iterate(func(field fieldpath.Path) {
+		if owners, found := lost[field.String()]; found {
+			result[field.String()] = owners
+		}
+	})
+	return result
+}
+
+// ownershipLossDiagnostics reports the fields returned by `lostOwnership`, as
+// warnings, or errors if `strict` is set.
+func ownershipLossDiagnostics(typeInfo generic.TypeInfo, lost map[string][]string, strict bool) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	for _, field := range slices.Sorted(maps.Keys(lost)) {
+		quoted := make([]string, 0, len(lost[field]))
+		for _, owner := range lost[field] {
+			quoted = append(quoted, fmt.Sprintf("%q", owner))
+		}
+		attrPath := typeInfo.Schema.AttributePath(path.Root("manifest"), field)
+		summary := "Field ownership lost"
+		detail := fmt.Sprintf(
+			"The field %s is now owned by %s. Applying it again will conflict unless `force_conflicts = true` is set; "+
+				"remove it from `manifest` (and add it to `ignore_fields`) to leave it to the other manager.",
+			field, strings.Join(quoted, ", "),
+		)
+		if strict {
+			diags.AddAttributeError(attrPath, summary, detail)
+		} else {
+			diags.AddAttributeWarning(attrPath, summary, detail)
+		}
+	}
+	return diags
+}
+
+// releasedFieldDiagnostics warns about fields that are removed from `manifest`,
+// but are also owned by other field managers. Applying the plan only releases
+// these, so they keep their current value. `result` is the object as returned
+// by a dry-run of the apply, and `managers` are our own field managers. Fields
+// in `ignored` were never applied, so are not released.
+func releasedFieldDiagnostics(
+	ctx context.Context,
+	typeInfo generic.TypeInfo,
+	prior types.KubernetesObjectValue,
+	plan types.KubernetesObjectValue,
+	ignored *fieldpath.Set,
+	result *unstructured.Unstructured,
+	managers ...string,
+) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	priorFields := &fieldpath.Set{}
+	diags.Append(prior.ManagedFields(ctx, path.Empty(), priorFields, nil)...)
+	planFields := &fieldpath.Set{}
+	diags.Append(plan.ManagedFields(ctx, path.Empty(), planFields, nil)...)
+	if diags.HasError() {
+		return diags
+	}
+
+	priorFields.Difference(planFields).RecursiveDifference(ignored).Leaves().Iterate(func(field fieldpath.Path) {
+		owners, ownerDiags := generic.FieldOwners(result, field)
+		diags.Append(ownerDiags...)
+		owners = slices.DeleteFunc(owners, func(owner string) bool { return slices.Contains(managers, owner) })
+		if len(owners) == 0 {
+			return
+		}
+
+		quoted := make([]string, 0, len(owners))
+		for _, owner := range owners {
+			quoted = append(quoted, fmt.Sprintf("%q", owner))
+		}
+		diags.AddAttributeWarning(
+			typeInfo.Schema.AttributePath(path.Root("manifest"), field.String()),
+			"Field will be released, not removed",
+			fmt.Sprintf(
+				"The field %s is removed from `manifest`, but is also owned by %s. "+
+					"It will no longer be managed by Terraform, but keeps its current value.",
+				field.String(), strings.Join(quoted, ", "),
+			),
+		)
+	})
+	return diags
+}
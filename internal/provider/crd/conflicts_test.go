@@ -0,0 +1,292 @@
+package crd_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/kwohlfahrt/tf-k8s/internal/generic"
+	"github.com/kwohlfahrt/tf-k8s/internal/provider/crd"
+	"github.com/kwohlfahrt/tf-k8s/internal/types"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
+)
+
+func exampleTypeInfo(t *testing.T) generic.TypeInfo {
+	var schema spec.Schema
+	err := json.Unmarshal([]byte(`{
+		"type": "object",
+		"properties": {
+			"spec": {
+				"type": "object",
+				"properties": {"fooBar": {"type": "string"}, "baz": {"type": "string"}}
+			}
+		}
+	}`), &schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	typ, err := types.OpenApiToTfType(nil, schema, []string{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return generic.TypeInfo{Kind: "Foo", Group: "example.com", Version: "v1", Schema: typ.(types.KubernetesObjectType)}
+}
+
+// conflictError builds the error returned by a server-side apply that
+// conflicts on each of `fields`, where `messages` are their cause messages.
+func conflictError(fields []string, messages []string) error {
+	causes := make([]metav1.StatusCause, 0, len(fields))
+	for i, field := range fields {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldManagerConflict,
+			Message: messages[i],
+			Field:   field,
+		})
+	}
+	return &errors.StatusError{ErrStatus: metav1.Status{
+		Status:  metav1.StatusFailure,
+		Code:    409,
+		Reason:  metav1.StatusReasonConflict,
+		Message: fmt.Sprintf("Apply failed with %d conflicts", len(causes)),
+		Details: &metav1.StatusDetails{Causes: causes},
+	}}
+}
+
+func TestApplyErrorDiagnostics(t *testing.T) {
+	typeInfo := exampleTypeInfo(t)
+	manifestPath := path.Root("manifest").AtName("spec")
+
+	cases := []struct {
+		name     string
+		err      error
+		expected diag.Diagnostics
+	}{
+		{
+			name: "matching",
+			err:  conflictError([]string{".spec.fooBar"}, []string{`conflict with "kubectl" using example.com/v1`}),
+			expected: diag.Diagnostics{diag.NewAttributeErrorDiagnostic(
+				manifestPath.AtName("foo_bar"),
+				"Unable to apply",
+				"The field .spec.fooBar is owned by the field manager \"kubectl\". Set `force_conflicts = true` to "+
+					"take ownership of it, or remove it from `manifest` (and add it to `ignore_fields`) to leave it "+
+					"to the other manager.",
+			)},
+		},
+		{
+			name: "not matching",
+			err:  conflictError([]string{".spec.baz"}, []string{"unexpected message"}),
+			expected: diag.Diagnostics{diag.NewAttributeErrorDiagnostic(
+				manifestPath.AtName("baz"),
+				"Unable to apply",
+				"The field .spec.baz is owned by another field manager. Set `force_conflicts = true` to take "+
+					"ownership of it, or remove it from `manifest` (and add it to `ignore_fields`) to leave it to "+
+					"the other manager.",
+			)},
+		},
+		{
+			name: "mixed",
+			err: conflictError(
+				[]string{".spec.fooBar", ".spec.baz"},
+				[]string{`conflict with "kubectl" using example.com/v1`, `conflict with "other" using example.com/v1`},
+			),
+			expected: diag.Diagnostics{
+				diag.NewAttributeErrorDiagnostic(
+					manifestPath.AtName("foo_bar"),
+					"Unable to apply",
+					"The field .spec.fooBar is owned by the field manager \"kubectl\". Set `force_conflicts = true` "+
+						"to take ownership of it, or remove it from `manifest` (and add it to `ignore_fields`) to "+
+						"leave it to the other manager.",
+				),
+				diag.NewAttributeErrorDiagnostic(
+					manifestPath.AtName("baz"),
+					"Unable to apply",
+					"The field .spec.baz is owned by the field manager \"other\". Set `force_conflicts = true` to "+
+						"take ownership of it, or remove it from `manifest` (and add it to `ignore_fields`) to leave "+
+						"it to the other manager.",
+				),
+			},
+		},
+		{
+			name: "not a conflict",
+			err:  errors.NewBadRequest("invalid object"),
+			expected: diag.Diagnostics{
+				diag.NewErrorDiagnostic("Unable to apply", "invalid object"),
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			diags := crd.ApplyErrorDiagnostics(typeInfo, "Unable to apply", c.err)
+			if !diags.Equal(c.expected) {
+				t.Errorf("expected %v, got %v", c.expected, diags)
+			}
+		})
+	}
+}
+
+func TestConflictsOnlyWith(t *testing.T) {
+	managers := sets.New("kubectl", "helm")
+
+	cases := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{
+			name:     "matching",
+			err:      conflictError([]string{".spec.fooBar"}, []string{`conflict with "kubectl" using example.com/v1`}),
+			expected: true,
+		},
+		{
+			name: "all matching",
+			err: conflictError(
+				[]string{".spec.fooBar", ".spec.baz"},
+				[]string{`conflict with "kubectl" using example.com/v1`, `conflict with "helm" using example.com/v1`},
+			),
+			expected: true,
+		},
+		{
+			name: "mixed",
+			err: conflictError(
+				[]string{".spec.fooBar", ".spec.baz"},
+				[]string{`conflict with "kubectl" using example.com/v1`, `conflict with "other" using example.com/v1`},
+			),
+			expected: false,
+		},
+		{
+			name:     "not matching",
+			err:      conflictError([]string{".spec.fooBar"}, []string{"unexpected message"}),
+			expected: false,
+		},
+		{
+			name:     "not a conflict",
+			err:      errors.NewBadRequest("invalid object"),
+			expected: false,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if actual := crd.ConflictsOnlyWith(c.err, managers); actual != c.expected {
+				t.Errorf("expected %t, got %t", c.expected, actual)
+			}
+		})
+	}
+}
+
+func TestLostOwnership(t *testing.T) {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	obj.SetManagedFields([]metav1.ManagedFieldsEntry{
+		{
+			Manager:    "ours",
+			Operation:  metav1.ManagedFieldsOperationApply,
+			APIVersion: "example.com/v1",
+			FieldsType: "FieldsV1",
+			FieldsV1:   &metav1.FieldsV1{Raw: []byte(`{"f:spec":{"f:baz":{}}}`)},
+		},
+		{
+			Manager:    "other",
+			Operation:  metav1.ManagedFieldsOperationApply,
+			APIVersion: "example.com/v1",
+			FieldsType: "FieldsV1",
+			FieldsV1:   &metav1.FieldsV1{Raw: []byte(`{"f:spec":{"f:fooBar":{}}}`)},
+		},
+	})
+	prior := fieldpath.NewSet(
+		fieldpath.MakePathOrDie("spec", "fooBar"),
+		fieldpath.MakePathOrDie("spec", "baz"),
+		// Removed by another manager, rather than taken over
+		fieldpath.MakePathOrDie("spec", "qux"),
+	)
+	owned := fieldpath.NewSet(fieldpath.MakePathOrDie("spec", "baz"))
+
+	lost, diags := crd.LostOwnership(prior, owned, obj)
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	expected := map[string][]string{".spec.fooBar": {"other"}}
+	if !reflect.DeepEqual(lost, expected) {
+		t.Errorf("expected %v, got %v", expected, lost)
+	}
+}
+
+func TestStillApplied(t *testing.T) {
+	lost := map[string][]string{".spec.fooBar": {"other"}, ".spec.baz": {"other"}}
+	applied := fieldpath.NewSet(fieldpath.MakePathOrDie("spec", "baz"))
+
+	expected := map[string][]string{".spec.baz": {"other"}}
+	if actual := crd.StillApplied(lost, applied); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+}
+
+func TestOwnershipLossDiagnostics(t *testing.T) {
+	typeInfo := exampleTypeInfo(t)
+	manifestPath := path.Root("manifest").AtName("spec")
+	lost := map[string][]string{".spec.fooBar": {"other", "kubectl"}, ".spec.baz": {"helm"}}
+	detail := "The field %s is now owned by %s. Applying it again will conflict unless `force_conflicts = true` " +
+		"is set; remove it from `manifest` (and add it to `ignore_fields`) to leave it to the other manager."
+
+	warnings := crd.OwnershipLossDiagnostics(typeInfo, lost, false)
+	expected := diag.Diagnostics{
+		diag.NewAttributeWarningDiagnostic(
+			manifestPath.AtName("baz"), "Field ownership lost", fmt.Sprintf(detail, ".spec.baz", `"helm"`),
+		),
+		diag.NewAttributeWarningDiagnostic(
+			manifestPath.AtName("foo_bar"), "Field ownership lost", fmt.Sprintf(detail, ".spec.fooBar", `"other", "kubectl"`),
+		),
+	}
+	if !warnings.Equal(expected) {
+		t.Errorf("expected %v, got %v", expected, warnings)
+	}
+
+	errs := crd.OwnershipLossDiagnostics(typeInfo, lost, true)
+	if errs.ErrorsCount() != len(lost) || errs.WarningsCount() != 0 {
+		t.Errorf("expected %d errors, got %v", len(lost), errs)
+	}
+}
+
+func TestOtherWorkspaceDiagnostics(t *testing.T) {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	obj.SetKind("Foo")
+	obj.SetName("foo")
+	entries := make([]metav1.ManagedFieldsEntry, 0)
+	for _, manager := range []string{"tofu-k8s-a", "tofu-k8s-b", "tofu-k8s", "tofu-k8sfoo", "kubectl"} {
+		entries = append(entries, metav1.ManagedFieldsEntry{
+			Manager:    manager,
+			Operation:  metav1.ManagedFieldsOperationApply,
+			APIVersion: "example.com/v1",
+			FieldsType: "FieldsV1",
+			FieldsV1:   &metav1.FieldsV1{Raw: []byte(`{"f:spec":{"f:foo":{}}}`)},
+		})
+	}
+	// Only applied fields are shared with another workspace
+	entries = append(entries, metav1.ManagedFieldsEntry{
+		Manager:    "tofu-k8s-c",
+		Operation:  metav1.ManagedFieldsOperationUpdate,
+		APIVersion: "example.com/v1",
+		FieldsType: "FieldsV1",
+		FieldsV1:   &metav1.FieldsV1{Raw: []byte(`{"f:spec":{"f:bar":{}}}`)},
+	})
+	obj.SetManagedFields(entries)
+
+	diags := crd.OtherWorkspaceDiagnostics(obj, "tofu-k8s-a")
+	detail := "The Foo foo is also managed by the field manager %q, which may be another Terraform workspace. " +
+		"Both will apply their configuration to it. Set the provider's `workspace` to tell workspaces apart."
+	expected := diag.Diagnostics{
+		diag.NewWarningDiagnostic("Object managed by another workspace", fmt.Sprintf(detail, "tofu-k8s-b")),
+		diag.NewWarningDiagnostic("Object managed by another workspace", fmt.Sprintf(detail, "tofu-k8s")),
+	}
+	if !diags.Equal(expected) {
+		t.Errorf("expected %v, got %v", expected, diags)
+	}
+}
@@ -0,0 +1,98 @@
+package crd_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/kwohlfahrt/tf-k8s/internal/provider"
+	"github.com/kwohlfahrt/tf-k8s/internal/provider/crd"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	runtimeschema "k8s.io/apimachinery/pkg/runtime/schema"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/util/csaupgrade"
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
+)
+
+const benchmarkFieldManager = "tofu-k8s-benchmark"
+
+// BenchmarkCreate compares creating objects against the previous approach of
+// creating, upgrading the field manager, then applying. It needs a cluster, e.g.
+// `KUBECONFIG=... go test -run '^$' -bench Create ./internal/provider/crd`.
+func BenchmarkCreate(b *testing.B) {
+	kubeconfig, err := os.ReadFile(os.Getenv("KUBECONFIG"))
+	if err != nil {
+		b.Skip("No cluster configured")
+	}
+	client, err := provider.MakeDynamicClient(kubeconfig)
+	if err != nil {
+		b.Fatal(err)
+	}
+	iface := client.Resource(runtimeschema.GroupVersionResource{Version: "v1", Resource: "configmaps"}).Namespace("default")
+	applied := fieldpath.NewSet(
+		fieldpath.MakePathOrDie("metadata", "name"),
+		fieldpath.MakePathOrDie("metadata", "namespace"),
+		fieldpath.MakePathOrDie("data", "foo"),
+	)
+
+	b.Run("create", func(b *testing.B) {
+		benchmarkCreate(b, iface, "benchmark-create", func(ctx context.Context, obj *unstructured.Unstructured) error {
+			_, err := crd.CreateObject(ctx, iface, obj, benchmarkFieldManager, applied, false)
+			return err
+		})
+	})
+
+	b.Run("create-upgrade-apply", func(b *testing.B) {
+		benchmarkCreate(b, iface, "benchmark-create-upgrade-apply", func(ctx context.Context, obj *unstructured.Unstructured) error {
+			created, err := iface.Create(ctx, obj, metav1.CreateOptions{FieldManager: benchmarkFieldManager})
+			if err != nil {
+				return err
+			}
+			patchData, err := csaupgrade.UpgradeManagedFieldsPatch(created, sets.New(benchmarkFieldManager), benchmarkFieldManager)
+			if err != nil {
+				return err
+			}
+			_, err = iface.Patch(ctx, obj.GetName(), k8stypes.JSONPatchType, patchData, metav1.PatchOptions{})
+			if err != nil {
+				return err
+			}
+			_, err = iface.Apply(ctx, obj.GetName(), obj, metav1.ApplyOptions{FieldManager: benchmarkFieldManager})
+			return err
+		})
+	})
+}
+
+func benchmarkCreate(
+	b *testing.B,
+	iface dynamic.ResourceInterface,
+	prefix string,
+	create func(context.Context, *unstructured.Unstructured) error,
+) {
+	ctx := context.Background()
+	names := make([]string, 0, b.N)
+	defer func() {
+		for _, name := range names {
+			if err := iface.Delete(ctx, name, metav1.DeleteOptions{}); err != nil {
+				b.Error(err)
+			}
+		}
+	}()
+
+	for i := 0; b.Loop(); i++ {
+		name := fmt.Sprintf("%s-%d", prefix, i)
+		obj := &unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata":   map[string]any{"name": name, "namespace": "default"},
+			"data":       map[string]any{"foo": "bar"},
+		}}
+		if err := create(ctx, obj); err != nil {
+			b.Fatal(err)
+		}
+		names = append(names, name)
+	}
+}
@@ -16,6 +16,11 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+type rolloutModel struct {
+	Status  basetypes.StringValue `tfsdk:"status"`
+	Message basetypes.StringValue `tfsdk:"message"`
+}
+
 type crdDataSource struct {
 	typeInfo generic.TypeInfo
 	client   *dynamic.DynamicClient
@@ -59,7 +64,30 @@ func (c *crdDataSource) Metadata(ctx context.Context, req datasource.MetadataReq
 func (c *crdDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
 	resp.Schema = schema.Schema{Attributes: map[string]schema.Attribute{
 		"manifest": generic.OpenApiToTfSchema(ctx, c.typeInfo, true),
+		"rollout": schema.SingleNestedAttribute{
+			MarkdownDescription: "Whether the object has finished rolling out, as computed by kstatus",
+			Computed:            true,
+			Attributes: map[string]schema.Attribute{
+				"status": schema.StringAttribute{
+					MarkdownDescription: "One of `InProgress`, `Failed`, `Current` or `Terminating`",
+					Computed:            true,
+				},
+				"message": schema.StringAttribute{Computed: true},
+			},
+		},
 	}}
+	for name, attribute := range serverMetadataDataSourceSchema() {
+		resp.Schema.Attributes[name] = attribute
+	}
+	if len(c.typeInfo.PrinterColumns) > 0 {
+		resp.Schema.Attributes["printer_columns"] = printerColumnsDataSourceSchema(c.typeInfo.PrinterColumns)
+	}
+	if c.typeInfo.Status != nil {
+		resp.Schema.Attributes["status"] = schema.DynamicAttribute{
+			MarkdownDescription: statusDescription,
+			Computed:            true,
+		}
+	}
 }
 
 func (c *crdDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
@@ -85,6 +113,20 @@ func (c *crdDataSource) Read(ctx context.Context, req datasource.ReadRequest, re
 		return
 	}
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("manifest"), basetypes.NewDynamicValue(state))...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("uid"), string(obj.GetUID()))...)
+	resp.Diagnostics.Append(setServerMetadata(ctx, &resp.State, obj)...)
+	resp.Diagnostics.Append(setStatus(ctx, c.typeInfo, &resp.State, obj)...)
+	resp.Diagnostics.Append(setPrinterColumns(ctx, c.typeInfo, &resp.State, obj)...)
+
+	status, message, err := generic.Rollout(obj)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("rollout"), "Unable to compute rollout status", err.Error())
+		return
+	}
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("rollout"), rolloutModel{
+		Status:  basetypes.NewStringValue(string(status)),
+		Message: basetypes.NewStringValue(message),
+	})...)
 }
 
 var (
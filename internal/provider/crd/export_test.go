@@ -0,0 +1,10 @@
+package crd
+
+var CreateObject = createObject
+
+var ApplyErrorDiagnostics = applyErrorDiagnostics
+var ConflictsOnlyWith = conflictsOnlyWith
+var LostOwnership = lostOwnership
+var StillApplied = stillApplied
+var OwnershipLossDiagnostics = ownershipLossDiagnostics
+var OtherWorkspaceDiagnostics = otherWorkspaceDiagnostics
@@ -13,6 +13,7 @@ terraform {
 
 provider "k8s" {
   kubeconfig = var.kubeconfig
+  workspace  = "core"
 }
 
 data "k8s_deployment_apps_v1" "foo" {
@@ -49,7 +50,14 @@ resource "k8s_deployment_apps_v1" "bar" {
         spec = {
           containers = [
             { name = "foo", image = "busybox" },
-            { name = "ubuntu", image = "ubuntu:22.04", liveness_probe = { http_get = { port = "healthz" } } },
+            {
+              name           = "ubuntu"
+              image          = "ubuntu:22.04"
+              liveness_probe = { http_get = { port = "healthz" } }
+              # `ports` is keyed by `containerPort` and `protocol`, which is
+              # defaulted by the server.
+              ports = [{ name = "healthz", container_port = 8080 }]
+            },
           ]
           # k8s doesn't include an empty volumes field in `managedFields`. Test
           # that we handle this properly.
@@ -58,6 +66,15 @@ resource "k8s_deployment_apps_v1" "bar" {
       }
     }
   }
+  wait_for_rollout = true
+  wait = {
+    observed_generation = true
+    conditions          = [{ type = "Available" }, { type = "Progressing", status = "True" }]
+    fields = [
+      { path = "status.observedGeneration" },
+      { path = "{.status.conditions[?(@.type==\"Progressing\")].reason}", value = "NewReplicaSetAvailable" },
+    ]
+  }
 }
 
 resource "k8s_configmap_v1" "bar" {
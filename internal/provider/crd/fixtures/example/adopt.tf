@@ -0,0 +1,30 @@
+variable "kubeconfig" {
+  type      = string
+  sensitive = true
+}
+
+terraform {
+  required_providers {
+    k8s = {
+      source = "registry.terraform.io/hashicorp/k8s"
+    }
+  }
+}
+
+provider "k8s" {
+  kubeconfig = var.kubeconfig
+}
+
+# Created by `kubectl apply --server-side`, see adopt.yaml
+resource "k8s_foo_example_com_v1" "adopted" {
+  manifest = {
+    metadata = {
+      name      = "adopted"
+      namespace = "default"
+      labels    = { "example.com/adopt" = "true" }
+    }
+    spec = { foo = "adopted" }
+  }
+  adopt_existing      = { labels = { "example.com/adopt" = "true" } }
+  adopt_from_managers = ["kubectl"]
+}
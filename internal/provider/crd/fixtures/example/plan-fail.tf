@@ -0,0 +1,24 @@
+variable "kubeconfig" {
+  type      = string
+  sensitive = true
+}
+
+terraform {
+  required_providers {
+    k8s = {
+      source = "registry.terraform.io/hashicorp/k8s"
+    }
+  }
+}
+
+provider "k8s" {
+  kubeconfig = var.kubeconfig
+}
+
+# Object names are validated by the server, so this is only caught by the dry-run
+resource "k8s_foo_example_com_v1" "invalid" {
+  manifest = {
+    metadata = { name = "Invalid_Name", namespace = "default" }
+    spec     = { foo = "invalid" }
+  }
+}
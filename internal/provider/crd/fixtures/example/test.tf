@@ -29,6 +29,10 @@ resource "k8s_foo_example_com_v1" "bar" {
     metadata = { name = "bar", namespace = "default" }
     spec     = { foo = "bar" }
   }
+  timeouts = { create = "1m", update = "1m", delete = "1m" }
+
+  outputs          = { foo = "spec.foo", missing = "status.missing" }
+  wait_for_outputs = false
 }
 
 resource "k8s_bar_example_com_v1" "bar" {
@@ -37,6 +41,9 @@ resource "k8s_bar_example_com_v1" "bar" {
     spec     = { bar = var.update ? "barbar" : "bar" }
   }
   field_manager = var.update ? "tofu-k8s" : "not-tofu-k8s"
+
+  deletion_propagation = "Foreground"
+  grace_period_seconds = 0
 }
 
 resource "k8s_foo_example_com_v1" "baz" {
@@ -46,11 +53,32 @@ resource "k8s_foo_example_com_v1" "baz" {
   }
 }
 
+resource "k8s_foo_example_com_v1" "abandoned" {
+  manifest = {
+    metadata = { name = "abandoned", namespace = "default" }
+    spec     = { foo = "abandoned" }
+  }
+  on_destroy = "abandon"
+}
+
 import {
   to = k8s_foo_example_com_v1.baz
   id = "kubectl:default/baz"
 }
 
+# `spec.bar` is also owned by kubectl, so removing it only releases it
+resource "k8s_foo_example_com_v1" "shared" {
+  manifest = {
+    metadata = { name = "shared", namespace = "default" }
+    spec     = { for k, v in { foo = "shared", bar = "shared" } : k => v if !var.update || k != "bar" }
+  }
+}
+
+import {
+  to = k8s_foo_example_com_v1.shared
+  id = "tofu-k8s:default/shared"
+}
+
 output "foo" {
   value = provider::k8s::parse_foo_example_com_v1({
     apiVersion = "example.com/v1"
@@ -0,0 +1,91 @@
+package crd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/kwohlfahrt/tf-k8s/internal/generic"
+	"github.com/kwohlfahrt/tf-k8s/internal/types"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
+)
+
+func ignoreFieldsSchema() schema.Attribute {
+	return schema.ListAttribute{
+		MarkdownDescription: "Paths that are left to other field managers: they are not applied even if set in `manifest`, and " +
+			"changes to them are not read back into state even if owned by `field_manager`, " +
+			"e.g. `spec.replicas` or `metadata.annotations[\"example.com/foo\"]`. " +
+			"Use this for fields managed by controllers or set by mutating webhooks. Fields of list elements (e.g. " +
+			"of `spec.template.spec.containers`) can't be addressed, only the whole list",
+		ElementType: basetypes.StringType{},
+		Optional:    true,
+	}
+}
+
+// ignoredFields returns the fields listed in `ignore_fields`. These are
+// excluded from the fields read back into `manifest`, e.g. because a mutating
+// webhook sets them on our behalf. Entries that are not yet known are skipped.
+func (c *crdResource) ignoredFields(ctx context.Context, config generic.PlanOrState) (*fieldpath.Set, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	ignored := &fieldpath.Set{}
+	var list basetypes.ListValue
+	diags.Append(config.GetAttribute(ctx, path.Root("ignore_fields"), &list)...)
+	if diags.HasError() || list.IsNull() || list.IsUnknown() {
+		return ignored, diags
+	}
+	var ignoreFields []basetypes.StringValue
+	diags.Append(list.ElementsAs(ctx, &ignoreFields, false)...)
+	if diags.HasError() {
+		return ignored, diags
+	}
+
+	for i, field := range ignoreFields {
+		if field.IsUnknown() || field.IsNull() {
+			continue
+		}
+		fieldPath, err := c.typeInfo.Schema.FieldPath(field.ValueString())
+		if err != nil {
+			diags.AddAttributeError(path.Root("ignore_fields").AtListIndex(i), "Invalid field path", err.Error())
+			continue
+		}
+		ignored.Insert(fieldPath)
+	}
+	return ignored, diags
+}
+
+// withIgnoredFields returns a copy of obj where the ignored fields have their
+// values in `manifest`, and the fields to read into state from it. The ignored
+// fields are never applied, so they keep their configured value in state
+// instead of reporting drift.
+func withIgnoredFields(
+	ctx context.Context,
+	manifest types.KubernetesObjectValue,
+	obj *unstructured.Unstructured,
+	owned *fieldpath.Set,
+	ignored *fieldpath.Set,
+) (*unstructured.Unstructured, *fieldpath.Set, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	manifestObj, objDiags := manifest.ToUnstructured(ctx, path.Empty())
+	diags.Append(objDiags...)
+	manifestFields := &fieldpath.Set{}
+	diags.Append(manifest.ManagedFields(ctx, path.Empty(), manifestFields, nil)...)
+	if diags.HasError() {
+		return nil, nil, diags
+	}
+	manifestMap, ok := manifestObj.(map[string]interface{})
+	if !ok {
+		diags.AddAttributeError(path.Root("manifest"), "Unexpected value type", fmt.Sprintf("Expected object, got %T", manifestObj))
+		return nil, nil, diags
+	}
+
+	result := obj.DeepCopy()
+	generic.CopyFields(result.Object, manifestMap, ignored)
+	configured := manifestFields.Difference(manifestFields.RecursiveDifference(ignored))
+	return result, owned.RecursiveDifference(ignored).Union(configured), diags
+}
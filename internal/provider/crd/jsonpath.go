@@ -0,0 +1,50 @@
+package crd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"k8s.io/client-go/util/jsonpath"
+)
+
+// evaluatePath evaluates a kubectl-style JSONPath expression against an
+// object. The surrounding braces and leading `.` may be omitted, so
+// `status.phase`, `.status.phase` and `{.status.phase}` are equivalent. Values
+// are formatted as by `kubectl get -o jsonpath`, and `found` is false if the
+// expression does not match anything.
+func evaluatePath(obj map[string]interface{}, expression string) (value string, found bool, err error) {
+	if !strings.HasPrefix(expression, "{") {
+		expression = fmt.Sprintf("{.%s}", strings.TrimPrefix(expression, "."))
+	}
+
+	j := jsonpath.New("").AllowMissingKeys(true)
+	if err := j.Parse(expression); err != nil {
+		return "", false, err
+	}
+	results, err := j.FindResults(obj)
+	if err != nil {
+		return "", false, err
+	}
+
+	values := make([]string, 0)
+	for _, result := range results {
+		for _, v := range result {
+			if !v.IsValid() || !v.CanInterface() || v.Interface() == nil {
+				continue
+			}
+			switch v := v.Interface().(type) {
+			case string:
+				values = append(values, v)
+			default:
+				encoded, err := json.Marshal(v)
+				if err != nil {
+					return "", false, err
+				}
+				values = append(values, string(encoded))
+			}
+		}
+	}
+
+	return strings.Join(values, " "), len(values) > 0, nil
+}
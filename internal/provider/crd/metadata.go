@@ -0,0 +1,71 @@
+package crd
+
+import (
+	"context"
+	"time"
+
+	datasourceschema "github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	resourceVersionDescription   = "The `metadata.resourceVersion` of the object, as of the last read"
+	generationDescription        = "The `metadata.generation` of the object, as of the last read"
+	creationTimestampDescription = "The `metadata.creationTimestamp` of the object"
+)
+
+// serverMetadataSchema returns attributes for metadata set by the server, which
+// is not part of `manifest`. The resource handles `uid` separately, as it
+// identifies the managed object rather than being refreshed.
+func serverMetadataSchema() map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"resource_version": schema.StringAttribute{
+			MarkdownDescription: resourceVersionDescription,
+			Computed:            true,
+		},
+		"generation": schema.Int64Attribute{
+			MarkdownDescription: generationDescription,
+			Computed:            true,
+		},
+		"creation_timestamp": schema.StringAttribute{
+			MarkdownDescription: creationTimestampDescription,
+			Computed:            true,
+			PlanModifiers:       []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
+		},
+	}
+}
+
+func serverMetadataDataSourceSchema() map[string]datasourceschema.Attribute {
+	return map[string]datasourceschema.Attribute{
+		"uid": datasourceschema.StringAttribute{
+			MarkdownDescription: "The `metadata.uid` of the object",
+			Computed:            true,
+		},
+		"resource_version": datasourceschema.StringAttribute{
+			MarkdownDescription: resourceVersionDescription,
+			Computed:            true,
+		},
+		"generation": datasourceschema.Int64Attribute{
+			MarkdownDescription: generationDescription,
+			Computed:            true,
+		},
+		"creation_timestamp": datasourceschema.StringAttribute{
+			MarkdownDescription: creationTimestampDescription,
+			Computed:            true,
+		},
+	}
+}
+
+func setServerMetadata(ctx context.Context, state *tfsdk.State, obj *unstructured.Unstructured) diag.Diagnostics {
+	var diags diag.Diagnostics
+	diags.Append(state.SetAttribute(ctx, path.Root("resource_version"), obj.GetResourceVersion())...)
+	diags.Append(state.SetAttribute(ctx, path.Root("generation"), obj.GetGeneration())...)
+	diags.Append(state.SetAttribute(ctx, path.Root("creation_timestamp"), obj.GetCreationTimestamp().UTC().Format(time.RFC3339))...)
+	return diags
+}
@@ -0,0 +1,82 @@
+package crd
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/kwohlfahrt/tf-k8s/internal/generic"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func outputsSchema() map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"outputs": schema.MapAttribute{
+			MarkdownDescription: "JSONPath expressions to evaluate against the object, e.g. " +
+				"`status.loadBalancer.ingress[0].ip`. The results are available in `output_values`",
+			ElementType: basetypes.StringType{},
+			Optional:    true,
+		},
+		"output_values": schema.MapAttribute{
+			MarkdownDescription: "The values of each of `outputs`, as of the last read. " +
+				"Outputs that do not match anything are null",
+			ElementType: basetypes.StringType{},
+			Computed:    true,
+		},
+		"wait_for_outputs": schema.BoolAttribute{
+			MarkdownDescription: "Wait for each of `outputs` to match a value after the object is created or updated",
+			Optional:            true,
+		},
+	}
+}
+
+// pendingOutput returns a description of the first output that does not match
+// anything in obj, or an empty string if all match.
+func pendingOutput(outputs map[string]string, obj *unstructured.Unstructured) (string, error) {
+	for _, name := range slices.Sorted(maps.Keys(outputs)) {
+		_, found, err := evaluatePath(obj.Object, outputs[name])
+		if err != nil {
+			return "", fmt.Errorf("unable to evaluate output %s: %w", name, err)
+		}
+		if !found {
+			return fmt.Sprintf("output %s (%s) to be set", name, outputs[name]), nil
+		}
+	}
+	return "", nil
+}
+
+func setOutputs(ctx context.Context, config generic.PlanOrState, state *tfsdk.State, obj *unstructured.Unstructured) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	var outputs map[string]string
+	diags.Append(config.GetAttribute(ctx, path.Root("outputs"), &outputs)...)
+	if diags.HasError() {
+		return diags
+	}
+
+	values := make(map[string]*string, len(outputs))
+	for name, expression := range outputs {
+		value, found, err := evaluatePath(obj.Object, expression)
+		if err != nil {
+			diags.AddAttributeError(path.Root("outputs").AtMapKey(name), "Unable to evaluate output", err.Error())
+			continue
+		}
+		if found {
+			values[name] = &value
+		} else {
+			values[name] = nil
+		}
+	}
+	if diags.HasError() {
+		return diags
+	}
+
+	diags.Append(state.SetAttribute(ctx, path.Root("output_values"), values)...)
+	return diags
+}
@@ -0,0 +1,145 @@
+package crd
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	datasourceschema "github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/kwohlfahrt/tf-k8s/internal/generic"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const printerColumnsDescription = "The additional printer columns of the resource type, as shown by `kubectl get`"
+
+func printerColumnType(column generic.PrinterColumn) attr.Type {
+	switch column.Type {
+	case "integer":
+		return basetypes.Int64Type{}
+	case "number":
+		return basetypes.Float64Type{}
+	case "boolean":
+		return basetypes.BoolType{}
+	default:
+		// `string` and `date`
+		return basetypes.StringType{}
+	}
+}
+
+func printerColumnsSchema(columns []generic.PrinterColumn) schema.Attribute {
+	attributes := make(map[string]schema.Attribute, len(columns))
+	for _, column := range columns {
+		switch printerColumnType(column).(type) {
+		case basetypes.Int64Type:
+			attributes[column.Attribute] = schema.Int64Attribute{MarkdownDescription: column.Description, Computed: true}
+		case basetypes.Float64Type:
+			attributes[column.Attribute] = schema.Float64Attribute{MarkdownDescription: column.Description, Computed: true}
+		case basetypes.BoolType:
+			attributes[column.Attribute] = schema.BoolAttribute{MarkdownDescription: column.Description, Computed: true}
+		default:
+			attributes[column.Attribute] = schema.StringAttribute{MarkdownDescription: column.Description, Computed: true}
+		}
+	}
+	return schema.SingleNestedAttribute{
+		MarkdownDescription: printerColumnsDescription,
+		Computed:            true,
+		Attributes:          attributes,
+	}
+}
+
+func printerColumnsDataSourceSchema(columns []generic.PrinterColumn) datasourceschema.Attribute {
+	attributes := make(map[string]datasourceschema.Attribute, len(columns))
+	for _, column := range columns {
+		switch printerColumnType(column).(type) {
+		case basetypes.Int64Type:
+			attributes[column.Attribute] = datasourceschema.Int64Attribute{MarkdownDescription: column.Description, Computed: true}
+		case basetypes.Float64Type:
+			attributes[column.Attribute] = datasourceschema.Float64Attribute{MarkdownDescription: column.Description, Computed: true}
+		case basetypes.BoolType:
+			attributes[column.Attribute] = datasourceschema.BoolAttribute{MarkdownDescription: column.Description, Computed: true}
+		default:
+			attributes[column.Attribute] = datasourceschema.StringAttribute{MarkdownDescription: column.Description, Computed: true}
+		}
+	}
+	return datasourceschema.SingleNestedAttribute{
+		MarkdownDescription: printerColumnsDescription,
+		Computed:            true,
+		Attributes:          attributes,
+	}
+}
+
+// printerColumnValue evaluates a column against obj. Like `kubectl get`, a
+// column that does not match anything, or can't be parsed as its type, is
+// empty (null).
+func printerColumnValue(column generic.PrinterColumn, obj *unstructured.Unstructured) (attr.Value, error) {
+	value, found, err := evaluatePath(obj.Object, column.JSONPath)
+	if err != nil {
+		return nil, err
+	}
+
+	switch printerColumnType(column).(type) {
+	case basetypes.Int64Type:
+		parsed, err := strconv.ParseInt(value, 10, 64)
+		if !found || err != nil {
+			return basetypes.NewInt64Null(), nil
+		}
+		return basetypes.NewInt64Value(parsed), nil
+	case basetypes.Float64Type:
+		parsed, err := strconv.ParseFloat(value, 64)
+		if !found || err != nil {
+			return basetypes.NewFloat64Null(), nil
+		}
+		return basetypes.NewFloat64Value(parsed), nil
+	case basetypes.BoolType:
+		parsed, err := strconv.ParseBool(value)
+		if !found || err != nil {
+			return basetypes.NewBoolNull(), nil
+		}
+		return basetypes.NewBoolValue(parsed), nil
+	default:
+		if !found {
+			return basetypes.NewStringNull(), nil
+		}
+		return basetypes.NewStringValue(value), nil
+	}
+}
+
+// setPrinterColumns sets the `printer_columns` attribute from obj, if the type
+// has any.
+func setPrinterColumns(ctx context.Context, typeInfo generic.TypeInfo, state *tfsdk.State, obj *unstructured.Unstructured) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if len(typeInfo.PrinterColumns) == 0 {
+		return diags
+	}
+
+	attrTypes := make(map[string]attr.Type, len(typeInfo.PrinterColumns))
+	attrs := make(map[string]attr.Value, len(typeInfo.PrinterColumns))
+	for _, column := range typeInfo.PrinterColumns {
+		value, err := printerColumnValue(column, obj)
+		if err != nil {
+			diags.AddAttributeError(
+				path.Root("printer_columns").AtName(column.Attribute),
+				"Unable to evaluate printer column", err.Error(),
+			)
+			continue
+		}
+		attrTypes[column.Attribute] = printerColumnType(column)
+		attrs[column.Attribute] = value
+	}
+	if diags.HasError() {
+		return diags
+	}
+
+	value, valueDiags := basetypes.NewObjectValue(attrTypes, attrs)
+	diags.Append(valueDiags...)
+	if diags.HasError() {
+		return diags
+	}
+	diags.Append(state.SetAttribute(ctx, path.Root("printer_columns"), value)...)
+	return diags
+}
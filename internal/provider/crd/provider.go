@@ -2,12 +2,15 @@ package crd
 
 import (
 	"context"
+	"fmt"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/function"
 	tfprovider "github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/kwohlfahrt/tf-k8s/internal/generic"
 	"github.com/kwohlfahrt/tf-k8s/internal/provider"
@@ -15,7 +18,8 @@ import (
 )
 
 type Clients struct {
-	dynamic *dynamic.DynamicClient
+	dynamic      *dynamic.DynamicClient
+	fieldManager string
 }
 
 type CrdProvider struct {
@@ -25,6 +29,7 @@ type CrdProvider struct {
 
 type CrdProviderModel struct {
 	Kubeconfig types.String `tfsdk:"kubeconfig"`
+	Workspace  types.String `tfsdk:"workspace"`
 }
 
 func (p *CrdProvider) Metadata(ctx context.Context, req tfprovider.MetadataRequest, resp *tfprovider.MetadataResponse) {
@@ -39,6 +44,14 @@ func (p *CrdProvider) Schema(ctx context.Context, req tfprovider.SchemaRequest,
 				MarkdownDescription: "Kubernetes Configuration",
 				Required:            true,
 			},
+			"workspace": schema.StringAttribute{
+				MarkdownDescription: "Identifies this configuration, e.g. `terraform.workspace`. When set, the default " +
+					"`field_manager` of resources is `tofu-k8s-<workspace>`, so objects managed by more than one " +
+					"workspace can be reported",
+				Optional: true,
+				// Field managers are limited to 128 characters
+				Validators: []validator.String{stringvalidator.LengthBetween(1, 128-len(defaultFieldManager+"-"))},
+			},
 		},
 	}
 }
@@ -56,7 +69,15 @@ func (p *CrdProvider) Configure(ctx context.Context, req tfprovider.ConfigureReq
 		return
 	}
 
-	clients := Clients{dynamic: dynamic}
+	fieldManager := defaultFieldManager
+	if data.Workspace.IsUnknown() {
+		// Not known until apply, so resources leave their default unknown
+		fieldManager = ""
+	} else if workspace := data.Workspace.ValueString(); workspace != "" {
+		fieldManager = fmt.Sprintf("%s-%s", defaultFieldManager, workspace)
+	}
+
+	clients := Clients{dynamic: dynamic, fieldManager: fieldManager}
 	resp.DataSourceData = clients
 	resp.ResourceData = clients
 }
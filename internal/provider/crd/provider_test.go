@@ -3,6 +3,7 @@ package crd_test
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
@@ -12,6 +13,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	runtimeschema "k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/dynamic"
 )
@@ -52,22 +54,94 @@ func checkNotExists(client *dynamic.DynamicClient, gvr metav1.GroupVersionResour
 	}
 }
 
+// checkOwned checks that `fieldManager` has applied fields of the object, and
+// no other field manager has.
+func checkOwned(client *dynamic.DynamicClient, gvr metav1.GroupVersionResource, meta metav1.ObjectMeta, fieldManager string) func(*terraform.State) error {
+	return func(*terraform.State) error {
+		schemaGvr := runtimeschema.GroupVersionResource{Group: gvr.Group, Version: gvr.Version, Resource: gvr.Resource}
+		obj, err := client.Resource(schemaGvr).Namespace(meta.Namespace).
+			Get(context.TODO(), meta.Name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		owned := false
+		for _, entry := range obj.GetManagedFields() {
+			if entry.Operation != metav1.ManagedFieldsOperationApply {
+				continue
+			}
+			if entry.Manager != fieldManager {
+				return fmt.Errorf("Resource '%s/%s' is also managed by %s", meta.Namespace, meta.Name, entry.Manager)
+			}
+			owned = true
+		}
+		if !owned {
+			return fmt.Errorf("Resource '%s/%s' is not managed by %s", meta.Namespace, meta.Name, fieldManager)
+		}
+		return nil
+	}
+}
+
+// checkAbandoned checks that the object still exists with the given field
+// values, but none of its fields are applied by `fieldManager`. The object is
+// then deleted, as it is no longer managed by the test.
+func checkAbandoned(
+	client *dynamic.DynamicClient,
+	gvr metav1.GroupVersionResource,
+	meta metav1.ObjectMeta,
+	fields map[string]string,
+	fieldManager string,
+) func(*terraform.State) error {
+	return func(*terraform.State) error {
+		schemaGvr := runtimeschema.GroupVersionResource{Group: gvr.Group, Version: gvr.Version, Resource: gvr.Resource}
+		iface := client.Resource(schemaGvr).Namespace(meta.Namespace)
+		obj, err := iface.Get(context.TODO(), meta.Name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		defer iface.Delete(context.TODO(), meta.Name, metav1.DeleteOptions{})
+
+		for _, entry := range obj.GetManagedFields() {
+			if entry.Manager == fieldManager && entry.Operation == metav1.ManagedFieldsOperationApply {
+				return fmt.Errorf("Resource '%s/%s' is still managed by %s", meta.Namespace, meta.Name, fieldManager)
+			}
+		}
+		for field, expected := range fields {
+			value, _, err := unstructured.NestedString(obj.Object, strings.Split(field, ".")...)
+			if err != nil {
+				return err
+			}
+			if value != expected {
+				return fmt.Errorf("Expected %s of '%s/%s' to be %q, got %q", field, meta.Namespace, meta.Name, expected, value)
+			}
+		}
+		return nil
+	}
+}
+
 type checkProperty struct {
 	Name  string
 	Path  []interface{}
 	Value interface{}
+	// Only check that the value is set, for values assigned by the server
+	NotNull bool
 }
 
 type checkResource struct {
 	GroupVersionResource metav1.GroupVersionResource
 	Metadata             metav1.ObjectMeta
+	// Expected values of string fields of the object, by dotted path
+	Fields map[string]string
 }
 
 type checkSpec struct {
 	Resources  []checkResource
+	Abandoned  []checkResource
 	Properties []checkProperty
 	State      []checkProperty
-	Outputs    []checkProperty
+	// Checked after the update step
+	UpdateState []checkProperty
+	Outputs     []checkProperty
 }
 
 func parsePath(path []interface{}) tfjsonpath.Path {
@@ -123,6 +197,17 @@ func makeChecks(client *dynamic.DynamicClient, spec []checkResource) resource.Te
 	return resource.ComposeAggregateTestCheckFunc(checks...)
 }
 
+func makeOwnedChecks(client *dynamic.DynamicClient, spec []checkResource, fieldManager string) resource.TestCheckFunc {
+	checks := make([]resource.TestCheckFunc, 0, len(spec))
+
+	for _, resource := range spec {
+		check := checkOwned(client, resource.GroupVersionResource, resource.Metadata, fieldManager)
+		checks = append(checks, check)
+	}
+
+	return resource.ComposeAggregateTestCheckFunc(checks...)
+}
+
 func makeConfigChecks(spec []checkProperty, outputSpec []checkProperty) resource.ConfigPlanChecks {
 	checks := make([]plancheck.PlanCheck, 0, len(spec)+len(outputSpec))
 
@@ -143,20 +228,29 @@ func makeStateChecks(spec []checkProperty) []statecheck.StateCheck {
 	checks := make([]statecheck.StateCheck, 0, len(spec))
 
 	for _, property := range spec {
-		check := statecheck.ExpectKnownValue(property.Name, parsePath(property.Path), parseValue(property.Value))
+		var value knownvalue.Check = knownvalue.NotNull()
+		if !property.NotNull {
+			value = parseValue(property.Value)
+		}
+		check := statecheck.ExpectKnownValue(property.Name, parsePath(property.Path), value)
 		checks = append(checks, check)
 	}
 
 	return checks
 }
 
-func makeDestroyChecks(client *dynamic.DynamicClient, resources []checkResource) resource.TestCheckFunc {
-	checks := make([]resource.TestCheckFunc, 0, len(resources))
+func makeDestroyChecks(client *dynamic.DynamicClient, resources []checkResource, abandoned []checkResource) resource.TestCheckFunc {
+	checks := make([]resource.TestCheckFunc, 0, len(resources)+len(abandoned))
 
 	for _, resource := range resources {
 		check := checkNotExists(client, resource.GroupVersionResource, resource.Metadata)
 		checks = append(checks, check)
 	}
 
+	for _, resource := range abandoned {
+		check := checkAbandoned(client, resource.GroupVersionResource, resource.Metadata, resource.Fields, "tofu-k8s")
+		checks = append(checks, check)
+	}
+
 	return resource.ComposeAggregateTestCheckFunc(checks...)
 }
@@ -2,14 +2,26 @@ package crd
 
 import (
 	"context"
+	"encoding/json"
+	stderrors "errors"
 	"fmt"
 	"strings"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	tfresource "github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
 	"github.com/kwohlfahrt/tf-k8s/internal/generic"
 	"github.com/kwohlfahrt/tf-k8s/internal/types"
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -17,15 +29,14 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	k8stypes "k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
-	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/dynamic"
-	"k8s.io/client-go/util/csaupgrade"
-	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
 )
 
 type crdResource struct {
-	typeInfo generic.TypeInfo
-	client   *dynamic.DynamicClient
+	typeInfo     generic.TypeInfo
+	client       *dynamic.DynamicClient
+	fieldManager string
 }
 
 func NewResource(typeInfo generic.TypeInfo) tfresource.Resource {
@@ -52,12 +63,25 @@ func (c *crdResource) Metadata(ctx context.Context, req tfresource.MetadataReque
 
 const defaultFieldManager string = "tofu-k8s"
 
+const objectReplacedDetail string = "The object was deleted or re-created outside Terraform. " +
+	"Refresh the state to plan a replacement."
+
+const objectNotDestroyedDetail string = "The object was re-created outside Terraform, so it is not managed by " +
+	"this resource and was left in place."
+
+const (
+	onDestroyDelete  string = "delete"
+	onDestroyAbandon string = "abandon"
+)
+
 func (c *crdResource) Schema(ctx context.Context, req tfresource.SchemaRequest, resp *tfresource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		Version: 0,
 		Attributes: map[string]schema.Attribute{
 			"manifest": generic.OpenApiToTfSchema(ctx, c.typeInfo, false),
 			"field_manager": schema.StringAttribute{
+				MarkdownDescription: "The field manager used to apply `manifest`. Defaults to `tofu-k8s`, or " +
+					"`tofu-k8s-<workspace>` if the provider's `workspace` is set",
 				Required: false,
 				Optional: true,
 				Computed: true,
@@ -69,8 +93,130 @@ func (c *crdResource) Schema(ctx context.Context, req tfresource.SchemaRequest,
 				Computed: true,
 				Default:  booldefault.StaticBool(false),
 			},
+			"uid": schema.StringAttribute{
+				MarkdownDescription: "The UID of the managed object. If the object is re-created outside Terraform, " +
+					"the resource is replaced",
+				Computed:      true,
+				PlanModifiers: []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
+			},
+			"wait":             waitSchema(),
+			"wait_for_rollout": waitForRolloutSchema(),
+			"deletion_propagation": schema.StringAttribute{
+				MarkdownDescription: "How dependents are deleted, one of `Foreground`, `Background` or `Orphan`. " +
+					"Defaults to the policy of the resource type, usually `Background`",
+				Optional: true,
+				Validators: []validator.String{stringvalidator.OneOf(
+					string(metav1.DeletePropagationForeground),
+					string(metav1.DeletePropagationBackground),
+					string(metav1.DeletePropagationOrphan),
+				)},
+			},
+			"grace_period_seconds": schema.Int64Attribute{
+				MarkdownDescription: "The duration in seconds before the object should be deleted, overriding the default of the resource type",
+				Optional:            true,
+				Validators:          []validator.Int64{int64validator.AtLeast(0)},
+			},
+			"on_destroy": schema.StringAttribute{
+				MarkdownDescription: "What to do with the object when the resource is destroyed. `delete` (the default) " +
+					"deletes the object, `abandon` leaves the object in place and releases the fields applied by `field_manager`, " +
+					"keeping their values",
+				Optional:   true,
+				Validators: []validator.String{stringvalidator.OneOf(onDestroyDelete, onDestroyAbandon)},
+			},
+			"strict_ownership": schema.BoolAttribute{
+				MarkdownDescription: "Fail to plan if another field manager has taken ownership of fields in `manifest` as of the " +
+					"last refresh, instead of warning. Has no effect if `force_conflicts` is set",
+				Optional: true,
+			},
+			"ignore_fields":       ignoreFieldsSchema(),
+			"adopt_from_managers": adoptFromManagersSchema(),
+			"adopt_existing":      adoptExistingSchema(),
+			"timeouts": timeouts.Attributes(ctx, timeouts.Opts{
+				Create: true,
+				Update: true,
+				Delete: true,
+			}),
 		},
 	}
+	for name, attribute := range serverMetadataSchema() {
+		resp.Schema.Attributes[name] = attribute
+	}
+	resp.Schema.Attributes["object"] = schema.DynamicAttribute{
+		MarkdownDescription: "The full object as of the last read, including its status and fields set by the server or " +
+			"other field managers",
+		Computed: true,
+	}
+	if len(c.typeInfo.PrinterColumns) > 0 {
+		resp.Schema.Attributes["printer_columns"] = printerColumnsSchema(c.typeInfo.PrinterColumns)
+	}
+	for name, attribute := range outputsSchema() {
+		resp.Schema.Attributes[name] = attribute
+	}
+	if c.typeInfo.Status != nil {
+		resp.Schema.Attributes["status"] = schema.DynamicAttribute{
+			MarkdownDescription: statusDescription,
+			Computed:            true,
+		}
+	}
+}
+
+// configAttributes are only read from the configuration, so are copied as-is
+// from the plan to the state after creating or updating the object.
+var configAttributes = []string{
+	"wait",
+	"wait_for_rollout",
+	"timeouts",
+	"deletion_propagation",
+	"grace_period_seconds",
+	"on_destroy",
+	"outputs",
+	"wait_for_outputs",
+	"strict_ownership",
+	"ignore_fields",
+	"adopt_from_managers",
+	"adopt_existing",
+}
+
+func copyConfigAttributes(ctx context.Context, plan generic.PlanOrState, state *tfsdk.State) diag.Diagnostics {
+	var diags diag.Diagnostics
+	for _, name := range configAttributes {
+		diags.Append(copyAttribute(ctx, plan, state, path.Root(name))...)
+	}
+	return diags
+}
+
+func copyAttribute(ctx context.Context, from generic.PlanOrState, to *tfsdk.State, p path.Path) diag.Diagnostics {
+	var value attr.Value
+	diags := from.GetAttribute(ctx, p, &value)
+	if diags.HasError() {
+		return diags
+	}
+	diags.Append(to.SetAttribute(ctx, p, value)...)
+	return diags
+}
+
+// setComputed sets all attributes that are computed from the live object.
+// Outputs are taken from `config`, which is the plan or prior state.
+func (c *crdResource) setComputed(ctx context.Context, config generic.PlanOrState, state *tfsdk.State, obj *unstructured.Unstructured) diag.Diagnostics {
+	var diags diag.Diagnostics
+	diags.Append(setServerMetadata(ctx, state, obj)...)
+	diags.Append(setStatus(ctx, c.typeInfo, state, obj)...)
+	diags.Append(setPrinterColumns(ctx, c.typeInfo, state, obj)...)
+	diags.Append(c.setObject(ctx, state, obj)...)
+	diags.Append(setOutputs(ctx, config, state, obj)...)
+	return diags
+}
+
+// setObject sets the `object` attribute to the full live object, including the
+// status and fields not owned by our field manager.
+func (c *crdResource) setObject(ctx context.Context, state *tfsdk.State, obj *unstructured.Unstructured) diag.Diagnostics {
+	var value types.KubernetesObjectValue
+	diags := generic.UnstructuredToValue(ctx, c.typeInfo.ObjectSchema(), *obj, nil, &value)
+	if diags.HasError() {
+		return diags
+	}
+	diags.Append(state.SetAttribute(ctx, path.Root("object"), basetypes.NewDynamicValue(value))...)
+	return diags
 }
 
 func (c *crdResource) Configure(ctx context.Context, req tfresource.ConfigureRequest, resp *tfresource.ConfigureResponse) {
@@ -88,9 +234,291 @@ func (c *crdResource) Configure(ctx context.Context, req tfresource.ConfigureReq
 		return
 	}
 	c.client = clients.dynamic
+	c.fieldManager = clients.fieldManager
+}
+
+// identityType returns the schema, with the fields that identify the object
+// additionally marked as immutable.
+func (c *crdResource) identityType() types.KubernetesObjectType {
+	identityFields := [][]string{{"metadata", "name"}}
+	if c.typeInfo.Namespaced {
+		identityFields = append(identityFields, []string{"metadata", "namespace"})
+	}
+
+	typ := c.typeInfo.Schema
+	for _, field := range identityFields {
+		// Every schema has metadata.name (and metadata.namespace if namespaced)
+		typ, _ = typ.WithImmutableField(field)
+	}
+	return typ
+}
+
+// Private state keys, under which Read records what it observed of the object
+// in the cluster, for ModifyPlan to check without fetching it again.
+const (
+	// The UID of the object
+	liveUIDKey = "live_uid"
+	// The fields in `manifest` that are now owned by other field managers, see
+	// `lostOwnership`
+	lostOwnershipKey = "lost_ownership"
+)
+
+type privateState interface {
+	GetKey(ctx context.Context, key string) ([]byte, diag.Diagnostics)
+	SetKey(ctx context.Context, key string, value []byte) diag.Diagnostics
+}
+
+func setPrivate(ctx context.Context, private privateState, key string, value any) diag.Diagnostics {
+	var diags diag.Diagnostics
+	raw, err := json.Marshal(value)
+	if err != nil {
+		diags.AddError("Unable to write private state", err.Error())
+		return diags
+	}
+	diags.Append(private.SetKey(ctx, key, raw)...)
+	return diags
+}
+
+// getPrivate reads `key` into value, and returns false if it is not set.
+func getPrivate(ctx context.Context, private privateState, key string, value any) (bool, diag.Diagnostics) {
+	raw, diags := private.GetKey(ctx, key)
+	if diags.HasError() || raw == nil {
+		return false, diags
+	}
+	if err := json.Unmarshal(raw, value); err != nil {
+		diags.AddError("Unable to read private state", err.Error())
+		return false, diags
+	}
+	return true, diags
+}
+
+func setLiveUID(ctx context.Context, private privateState, obj *unstructured.Unstructured) diag.Diagnostics {
+	return setPrivate(ctx, private, liveUIDKey, string(obj.GetUID()))
+}
+
+// replacedOutOfBand checks if the object in the cluster had a different UID to
+// the one in state as of the last read, i.e. it was deleted and re-created
+// outside Terraform.
+func replacedOutOfBand(ctx context.Context, state generic.PlanOrState, private privateState) (bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	var uid *string
+	diags.Append(state.GetAttribute(ctx, path.Root("uid"), &uid)...)
+	if diags.HasError() || uid == nil {
+		return false, diags
+	}
+
+	var liveUID string
+	found, privateDiags := getPrivate(ctx, private, liveUIDKey, &liveUID)
+	diags.Append(privateDiags...)
+	if diags.HasError() || !found {
+		return false, diags
+	}
+	return liveUID != *uid, diags
+}
+
+func (c *crdResource) ModifyPlan(ctx context.Context, req tfresource.ModifyPlanRequest, resp *tfresource.ModifyPlanResponse) {
+	// Nothing to check when destroying
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	// Without an explicit `field_manager`, use the default for the workspace.
+	// This isn't known until the provider is configured.
+	var configFieldManager basetypes.StringValue
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("field_manager"), &configFieldManager)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if configFieldManager.IsNull() && c.fieldManager != "" {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("field_manager"), c.fieldManager)...)
+	} else if configFieldManager.IsNull() {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("field_manager"), basetypes.NewStringUnknown())...)
+	}
+
+	var state types.KubernetesObjectValue
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("manifest"), &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var priorState types.KubernetesObjectValue
+	if !req.State.Raw.IsNull() {
+		resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("manifest"), &priorState)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		changes, diags := c.identityType().ImmutableChanges(ctx, path.Root("manifest"), priorState, state)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		if len(changes) > 0 {
+			// The object will be re-created, so an apply would fail the dry-run
+			resp.RequiresReplace.Append(changes...)
+			return
+		}
+
+		replaced, diags := replacedOutOfBand(ctx, req.State, req.Private)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		if replaced {
+			resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("uid"), basetypes.NewStringUnknown())...)
+			resp.RequiresReplace.Append(path.Root("uid"))
+			return
+		}
+
+		var strictOwnership basetypes.BoolValue
+		resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("strict_ownership"), &strictOwnership)...)
+		var forceConflicts basetypes.BoolValue
+		resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("force_conflicts"), &forceConflicts)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		// Lost fields are taken back by a forced apply, and can be left to
+		// their new owners by no longer applying them.
+		if strictOwnership.ValueBool() && !forceConflicts.ValueBool() {
+			var lost map[string][]string
+			_, diags := getPrivate(ctx, req.Private, lostOwnershipKey, &lost)
+			resp.Diagnostics.Append(diags...)
+			ignored, ignoredDiags := c.ignoredFields(ctx, req.Plan)
+			resp.Diagnostics.Append(ignoredDiags...)
+			planFields := &fieldpath.Set{}
+			resp.Diagnostics.Append(state.ManagedFields(ctx, path.Empty(), planFields, nil)...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+			lost = stillApplied(lost, planFields.RecursiveDifference(ignored))
+			resp.Diagnostics.Append(ownershipLossDiagnostics(c.typeInfo, lost, true)...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+		}
+	}
+
+	tfState, err := state.ToTerraformValue(ctx)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("manifest"), "Unable to read manifest", err.Error())
+		return
+	}
+	if !tfState.IsFullyKnown() || c.client == nil {
+		// The manifest (or provider configuration) depends on other resources,
+		// so can't be checked yet
+		return
+	}
+
+	var meta generic.ObjectMeta
+	resp.Diagnostics.Append(generic.StateToObjectMeta(ctx, req.Plan, c.typeInfo, &meta)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	var fieldManager basetypes.StringValue
+	resp.Diagnostics.Append(resp.Plan.GetAttribute(ctx, path.Root("field_manager"), &fieldManager)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	var forceConflicts basetypes.BoolValue
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("force_conflicts"), &forceConflicts)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if fieldManager.IsUnknown() || forceConflicts.IsUnknown() {
+		return
+	}
+	adopt, adoptKnown, diags := adoptExisting(ctx, req.Plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	adoptManagers, adoptManagersKnown, diags := adoptedManagers(ctx, req.Plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() || !adoptKnown || !adoptManagersKnown {
+		return
+	}
+
+	ignored, diags := c.ignoredFields(ctx, req.Plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	planObj, diags := generic.ValueToUnstructured(ctx, state, c.typeInfo, ignored)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Run the same operation as `Create` or `Update` as a dry-run, so that
+	// admission errors, conflicts etc. are reported during the plan.
+	iface := c.typeInfo.Interface(c.client, meta.Namespace)
+	dryRun := []string{metav1.DryRunAll}
+	// Fields owned by managers we adopt from, or our previous field manager,
+	// are transferred to us before the apply, so can't conflict.
+	var priorFieldManager *string
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("field_manager"), &priorFieldManager)...)
+	if priorFieldManager != nil {
+		adoptManagers.Insert(*priorFieldManager)
+	}
+	dryRunApply := func() (*unstructured.Unstructured, error) {
+		result, err := iface.Apply(ctx, meta.Name, planObj, metav1.ApplyOptions{
+			FieldManager: fieldManager.ValueString(), Force: forceConflicts.ValueBool(), DryRun: dryRun,
+		})
+		if err != nil && conflictsOnlyWith(err, adoptManagers) {
+			result, err = iface.Apply(ctx, meta.Name, planObj, metav1.ApplyOptions{
+				FieldManager: fieldManager.ValueString(), Force: true, DryRun: dryRun,
+			})
+		}
+		return result, err
+	}
+
+	if req.State.Raw.IsNull() {
+		_, err = iface.Create(ctx, planObj, metav1.CreateOptions{FieldManager: fieldManager.ValueString(), DryRun: dryRun})
+		if errors.IsAlreadyExists(err) && adopt != nil {
+			var existing *unstructured.Unstructured
+			existing, err = iface.Get(ctx, meta.Name, metav1.GetOptions{})
+			if err == nil {
+				resp.Diagnostics.Append(checkAdoptable(existing, *adopt)...)
+				_, err = dryRunApply()
+			}
+		}
+	} else {
+		var result *unstructured.Unstructured
+		result, err = dryRunApply()
+		if err == nil && priorFieldManager != nil {
+			// Fields that were ignored were never applied, so can't be released
+			priorIgnored, diags := c.ignoredFields(ctx, req.State)
+			resp.Diagnostics.Append(diags...)
+			// The previous field manager is renamed by the update, if it has changed
+			resp.Diagnostics.Append(releasedFieldDiagnostics(
+				ctx, c.typeInfo, priorState, state, priorIgnored, result, fieldManager.ValueString(), *priorFieldManager,
+			)...)
+		}
+	}
+	if errors.IsNotFound(err) {
+		// The namespace (or the type itself) may be created by the same plan
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.Append(applyErrorDiagnostics(c.typeInfo, "Unable to dry-run resource", err)...)
+	}
 }
 
 func (c *crdResource) Create(ctx context.Context, req tfresource.CreateRequest, resp *tfresource.CreateResponse) {
+	var timeout timeouts.Value
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("timeouts"), &timeout)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	createTimeout, diags := timeout.Create(ctx, defaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
 	var meta generic.ObjectMeta
 	resp.Diagnostics.Append(generic.StateToObjectMeta(ctx, req.Plan, c.typeInfo, &meta)...)
 	if resp.Diagnostics.HasError() {
@@ -112,53 +540,68 @@ func (c *crdResource) Create(ctx context.Context, req tfresource.CreateRequest,
 	if resp.Diagnostics.HasError() {
 		return
 	}
-	planObj, diags := generic.ValueToUnstructured(ctx, state, c.typeInfo)
+	ignored, diags := c.ignoredFields(ctx, req.Plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	planObj, diags := generic.ValueToUnstructured(ctx, state, c.typeInfo, ignored)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	iface := c.typeInfo.Interface(c.client, meta.Namespace)
-	// Use a `Create` operation, to ensure we are creating the resource.  This
-	// ensures two terraform operations can't both create the same object. See
-	// also github.com/kubernetes/kubernetes#116156
-	obj, err := iface.
-		Create(ctx, planObj, metav1.CreateOptions{FieldManager: fieldManager})
-	if err != nil {
-		resp.Diagnostics.AddError("Unable to create resource", err.Error())
+	adopt, _, diags := adoptExisting(ctx, req.Plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	adoptManagers, _, diags := adoptedManagers(ctx, req.Plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	applied := &fieldpath.Set{}
+	resp.Diagnostics.Append(state.ManagedFields(ctx, path.Empty(), applied, nil)...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
-		// We have created the object, so all fields are owned by `fieldManager`,
-		// but with `operation: Update`. This produces a conflict when we try to
-		// server-side apply changes in `Update()`. Use `csaupgrade` to migrate the
-		// field manager to `operation: Apply`. This might be better in `Update()`.
-		patchData, err := csaupgrade.UpgradeManagedFieldsPatch(obj, sets.New(fieldManager), fieldManager)
+	iface := c.typeInfo.Interface(c.client, meta.Namespace)
+	obj, err := createObject(ctx, iface, planObj, fieldManager, applied, *forceConflicts)
+	if errors.IsAlreadyExists(err) && adopt != nil {
+		// Unless we are explicitly asked to adopt the existing object
+		obj, err = iface.Get(ctx, meta.Name, metav1.GetOptions{})
 		if err != nil {
-			return err
+			resp.Diagnostics.AddError("Unable to fetch resource", err.Error())
+			return
+		}
+		resp.Diagnostics.Append(checkAdoptable(obj, *adopt)...)
+		if resp.Diagnostics.HasError() {
+			return
 		}
 
-		obj, err = iface.Patch(ctx, meta.Name, k8stypes.JSONPatchType, patchData, metav1.PatchOptions{})
-		if err != nil && errors.IsConflict(err) {
-			newObj, getErr := iface.Get(ctx, meta.Name, metav1.GetOptions{})
-			if getErr != nil {
-				return getErr
-			}
-			obj = newObj
+		// Any fields we previously set without applying them would conflict
+		obj, err = adoptManagedFields(ctx, iface, obj, adoptManagers.Insert(fieldManager), fieldManager)
+		if err != nil {
+			resp.Diagnostics.AddError("Unable to patch field-manager", err.Error())
+			return
 		}
-		return err
-	})
-	if err != nil {
-		resp.Diagnostics.AddError("Unable to patch field-manager", err.Error())
-		return
-	}
 
-	// Apply to update the list of tracked fields to match `planObj`, not the entire object creation.
-	obj, err = iface.Apply(ctx, meta.Name, planObj, metav1.ApplyOptions{FieldManager: fieldManager})
-	if err != nil {
-		resp.Diagnostics.AddError("Unable to update resource", err.Error())
+		// The UID ensures we don't re-create the object if it is deleted in the
+		// meantime.
+		planObj.SetUID(obj.GetUID())
+		obj, err = iface.Apply(ctx, meta.Name, planObj, metav1.ApplyOptions{FieldManager: fieldManager, Force: *forceConflicts})
+		if err != nil {
+			resp.Diagnostics.Append(applyErrorDiagnostics(c.typeInfo, "Unable to update resource", err)...)
+			return
+		}
+	} else if err != nil {
+		resp.Diagnostics.AddError("Unable to create resource", err.Error())
+		return
 	}
+	resp.Diagnostics.Append(otherWorkspaceDiagnostics(obj, fieldManager)...)
 
 	fields, diags := generic.GetManagedFieldSet(obj, fieldManager)
 	resp.Diagnostics.Append(diags...)
@@ -166,10 +609,12 @@ func (c *crdResource) Create(ctx context.Context, req tfresource.CreateRequest,
 		return
 	}
 	resp.Diagnostics.Append(state.ManagedFields(ctx, path.Empty(), fields, nil)...)
+	readObj, fields, diags := withIgnoredFields(ctx, state, obj, fields, ignored)
+	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
-	resp.Diagnostics.Append(generic.UnstructuredToValue(ctx, c.typeInfo.Schema, *obj, fields.Leaves(), &state)...)
+	resp.Diagnostics.Append(generic.UnstructuredToValue(ctx, c.typeInfo.Schema, *readObj, fields.Leaves(), &state)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -177,6 +622,20 @@ func (c *crdResource) Create(ctx context.Context, req tfresource.CreateRequest,
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("manifest"), state)...)
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("field_manager"), fieldManager)...)
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("force_conflicts"), forceConflicts)...)
+	resp.Diagnostics.Append(c.setComputed(ctx, req.Plan, &resp.State, obj)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("uid"), string(obj.GetUID()))...)
+	resp.Diagnostics.Append(setLiveUID(ctx, resp.Private, obj)...)
+	resp.Diagnostics.Append(copyConfigAttributes(ctx, req.Plan, &resp.State)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	last, diags := c.wait(ctx, req.Plan, meta)
+	resp.Diagnostics.Append(diags...)
+	if last != nil {
+		// Record what was observed while waiting, even if it failed
+		resp.Diagnostics.Append(c.setComputed(ctx, req.Plan, &resp.State, last)...)
+	}
 }
 
 func (c *crdResource) Read(ctx context.Context, req tfresource.ReadRequest, resp *tfresource.ReadResponse) {
@@ -211,17 +670,37 @@ func (c *crdResource) Read(ctx context.Context, req tfresource.ReadRequest, resp
 		return
 	}
 
+	resp.Diagnostics.Append(otherWorkspaceDiagnostics(obj, fieldManager)...)
 	fields, diags := generic.GetManagedFieldSet(obj, fieldManager)
 	resp.Diagnostics.Append(diags...)
 	if diags.HasError() {
 		return
 	}
-	resp.Diagnostics.Append(state.ManagedFields(ctx, path.Empty(), fields, nil)...)
+
+	priorFields := &fieldpath.Set{}
+	resp.Diagnostics.Append(state.ManagedFields(ctx, path.Empty(), priorFields, nil)...)
+	ignored, diags := c.ignoredFields(ctx, req.State)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	// Only warn, so the state can still be refreshed. With `strict_ownership`,
+	// the plan fails instead. Ignored fields were never applied, so can't be
+	// lost.
+	lost, diags := lostOwnership(priorFields.RecursiveDifference(ignored), fields, obj)
+	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	resp.Diagnostics.Append(ownershipLossDiagnostics(c.typeInfo, lost, false)...)
+	resp.Diagnostics.Append(setPrivate(ctx, resp.Private, lostOwnershipKey, lost)...)
 
-	resp.Diagnostics.Append(generic.UnstructuredToValue(ctx, c.typeInfo.Schema, *obj, fields.Leaves(), &state)...)
+	readObj, fields, diags := withIgnoredFields(ctx, state, obj, fields.Union(priorFields), ignored)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(generic.UnstructuredToValue(ctx, c.typeInfo.Schema, *readObj, fields.Leaves(), &state)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -229,9 +708,33 @@ func (c *crdResource) Read(ctx context.Context, req tfresource.ReadRequest, resp
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("manifest"), state)...)
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("field_manager"), fieldManager)...)
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("force_conflicts"), forceConflicts)...)
+	resp.Diagnostics.Append(c.setComputed(ctx, req.State, &resp.State, obj)...)
+
+	resp.Diagnostics.Append(setLiveUID(ctx, resp.Private, obj)...)
+
+	// Only fill in a missing UID (e.g. after import). If the object has been
+	// re-created, the UID in state no longer matches and a replacement is planned.
+	var uid *string
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("uid"), &uid)...)
+	if uid == nil {
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("uid"), string(obj.GetUID()))...)
+	}
 }
 
 func (c *crdResource) Update(ctx context.Context, req tfresource.UpdateRequest, resp *tfresource.UpdateResponse) {
+	var timeout timeouts.Value
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("timeouts"), &timeout)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	updateTimeout, diags := timeout.Update(ctx, defaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
 	var meta generic.ObjectMeta
 	resp.Diagnostics.Append(generic.StateToObjectMeta(ctx, req.State, c.typeInfo, &meta)...)
 	if resp.Diagnostics.HasError() {
@@ -253,34 +756,46 @@ func (c *crdResource) Update(ctx context.Context, req tfresource.UpdateRequest,
 		return
 	}
 
-	if fieldManager != planFieldManager {
-		var oldState types.KubernetesObjectValue
-		resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("manifest"), &oldState)...)
-		if resp.Diagnostics.HasError() {
-			return
-		}
-		obj, diags := generic.ValueToUnstructured(ctx, oldState, c.typeInfo)
-		resp.Diagnostics.Append(diags...)
-		if resp.Diagnostics.HasError() {
-			return
-		}
+	var uid *string
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("uid"), &uid)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	iface := c.typeInfo.Interface(c.client, meta.Namespace)
+	current, err := iface.Get(ctx, meta.Name, metav1.GetOptions{})
+	if errors.IsNotFound(err) || (err == nil && uid != nil && string(current.GetUID()) != *uid) {
+		resp.Diagnostics.AddError("Unable to update resource", objectReplacedDetail)
+		return
+	} else if err != nil {
+		resp.Diagnostics.AddError("Unable to fetch resource", err.Error())
+		return
+	}
+	// Each apply includes the UID, so it fails rather than re-creating the object
+	// if it is deleted in the meantime.
+	currentUID := current.GetUID()
 
-		_, err := c.typeInfo.Interface(c.client, meta.Namespace).
-			Apply(ctx, meta.Name, obj, metav1.ApplyOptions{FieldManager: planFieldManager, Force: *forceConflicts})
+	adopt, _, diags := adoptedManagers(ctx, req.Plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if adopt.Len() > 0 {
+		// Transfer to the prior field manager, which is then renamed below if
+		// it has changed.
+		current, err = adoptManagedFields(ctx, iface, current, adopt, fieldManager)
 		if err != nil {
-			resp.Diagnostics.AddError("Unable to apply new fieldManager", err.Error())
+			resp.Diagnostics.AddError("Unable to adopt fields from other managers", err.Error())
 			return
 		}
+	}
 
-		empty := unstructured.Unstructured{Object: map[string]interface{}{
-			"apiVersion": c.typeInfo.GroupVersionResource().GroupVersion().String(),
-			"kind":       c.typeInfo.Kind,
-			"metadata":   map[string]interface{}{"name": meta.Name, "namespace": meta.Namespace},
-		}}
-		_, err = c.typeInfo.Interface(c.client, meta.Namespace).
-			Apply(ctx, meta.Name, &empty, metav1.ApplyOptions{FieldManager: fieldManager})
+	if fieldManager != planFieldManager {
+		// Rename our entry in `managedFields` in place, so the object is never
+		// owned by both field managers and no fields are taken from others.
+		_, err = renameFieldManager(ctx, iface, current, fieldManager, planFieldManager)
 		if err != nil {
-			resp.Diagnostics.AddError("Unable to remove old fieldManager", err.Error())
+			resp.Diagnostics.AddError("Unable to rename field manager", err.Error())
 			return
 		}
 	}
@@ -290,18 +805,24 @@ func (c *crdResource) Update(ctx context.Context, req tfresource.UpdateRequest,
 	if resp.Diagnostics.HasError() {
 		return
 	}
-	obj, diags := generic.ValueToUnstructured(ctx, state, c.typeInfo)
+	ignored, diags := c.ignoredFields(ctx, req.Plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	obj, diags := generic.ValueToUnstructured(ctx, state, c.typeInfo, ignored)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	// TODO: Validate that the object already exists. This will silently create
-	// the object if it does not already exist.
-	obj, err := c.typeInfo.Interface(c.client, meta.Namespace).
-		Apply(ctx, meta.Name, obj, metav1.ApplyOptions{FieldManager: planFieldManager, Force: *forceConflicts})
-	if err != nil {
-		resp.Diagnostics.AddError("Unable to update resource", err.Error())
+	obj.SetUID(currentUID)
+	obj, err = iface.Apply(ctx, meta.Name, obj, metav1.ApplyOptions{FieldManager: planFieldManager, Force: *forceConflicts})
+	if errors.IsNotFound(err) {
+		resp.Diagnostics.AddError("Unable to update resource", objectReplacedDetail)
+		return
+	} else if err != nil {
+		resp.Diagnostics.Append(applyErrorDiagnostics(c.typeInfo, "Unable to update resource", err)...)
 		return
 	}
 
@@ -311,46 +832,220 @@ func (c *crdResource) Update(ctx context.Context, req tfresource.UpdateRequest,
 		return
 	}
 	resp.Diagnostics.Append(state.ManagedFields(ctx, path.Empty(), fields, nil)...)
+	readObj, fields, diags := withIgnoredFields(ctx, state, obj, fields, ignored)
+	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	resp.Diagnostics.Append(generic.UnstructuredToValue(ctx, c.typeInfo.Schema, *obj, fields.Leaves(), &state)...)
+	resp.Diagnostics.Append(generic.UnstructuredToValue(ctx, c.typeInfo.Schema, *readObj, fields.Leaves(), &state)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("manifest"), state)...)
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("field_manager"), planFieldManager)...)
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("force_conflicts"), forceConflicts)...)
+	resp.Diagnostics.Append(c.setComputed(ctx, req.Plan, &resp.State, obj)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("uid"), string(obj.GetUID()))...)
+	resp.Diagnostics.Append(setLiveUID(ctx, resp.Private, obj)...)
+	// The apply either took back any fields we lost, or failed with a conflict
+	resp.Diagnostics.Append(setPrivate(ctx, resp.Private, lostOwnershipKey, nil)...)
+	resp.Diagnostics.Append(copyConfigAttributes(ctx, req.Plan, &resp.State)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	last, diags := c.wait(ctx, req.Plan, meta)
+	resp.Diagnostics.Append(diags...)
+	if last != nil {
+		// Record what was observed while waiting, even if it failed
+		resp.Diagnostics.Append(c.setComputed(ctx, req.Plan, &resp.State, last)...)
+	}
 }
 
 func (c *crdResource) Delete(ctx context.Context, req tfresource.DeleteRequest, resp *tfresource.DeleteResponse) {
+	var timeout timeouts.Value
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("timeouts"), &timeout)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	deleteTimeout, diags := timeout.Delete(ctx, defaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
 	var meta generic.ObjectMeta
 	resp.Diagnostics.Append(generic.StateToObjectMeta(ctx, req.State, c.typeInfo, &meta)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	fieldSelector := fmt.Sprintf("metadata.name=%s", meta.Name)
-	w, err := c.client.Resource(c.typeInfo.GroupVersionResource()).
-		Watch(ctx, metav1.ListOptions{FieldSelector: fieldSelector})
-	if err != nil {
-		resp.Diagnostics.AddError("Unable to watch resource for deletion", err.Error())
+	var onDestroy *string
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("on_destroy"), &onDestroy)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if onDestroy != nil && *onDestroy == onDestroyAbandon {
+		resp.Diagnostics.Append(c.abandon(ctx, req.State, meta)...)
+		return
+	}
+
+	var propagation *string
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("deletion_propagation"), &propagation)...)
+	var gracePeriodSeconds *int64
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("grace_period_seconds"), &gracePeriodSeconds)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	iface := c.typeInfo.Interface(c.client, meta.Namespace)
+	// Fetch the object first, so the watch below starts from a consistent
+	// resourceVersion.
+	obj, err := iface.Get(ctx, meta.Name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return
+	} else if err != nil {
+		resp.Diagnostics.AddError("Unable to fetch resource for deletion", err.Error())
 		return
 	}
-	defer w.Stop()
 
-	err = c.typeInfo.Interface(c.client, meta.Namespace).Delete(ctx, meta.Name, metav1.DeleteOptions{})
-	if err != nil && !errors.IsNotFound(err) {
+	// Only delete the instance of the object that this resource created
+	uid, diags := stateUID(ctx, req.State, obj)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if uid != obj.GetUID() {
+		resp.Diagnostics.AddWarning("Resource not deleted", objectNotDestroyedDetail)
+		return
+	}
+	options := metav1.DeleteOptions{
+		Preconditions:      &metav1.Preconditions{UID: &uid},
+		GracePeriodSeconds: gracePeriodSeconds,
+	}
+	if propagation != nil {
+		policy := metav1.DeletionPropagation(*propagation)
+		options.PropagationPolicy = &policy
+	}
+	err = iface.Delete(ctx, meta.Name, options)
+	if errors.IsNotFound(err) {
+		return
+	} else if errors.IsConflict(err) {
+		// The object was deleted and re-created since we fetched it
+		resp.Diagnostics.AddWarning("Resource not deleted", objectNotDestroyedDetail)
+		return
+	} else if err != nil {
 		resp.Diagnostics.AddError("Unable to delete resource", err.Error())
 		return
 	}
 
-	for event := range w.ResultChan() {
-		if event.Type == watch.Deleted {
-			break
+	last, err := waitForDeletion(ctx, iface, obj)
+	if err != nil {
+		summary := "Unable to wait for deletion"
+		if ctx.Err() != nil {
+			summary = "Timed out waiting for deletion"
 		}
+		resp.Diagnostics.AddError(summary, fmt.Sprintf("%s\n\n%s", err.Error(), formatDeletion(last)))
+	}
+}
+
+// createObject creates obj, as if it had been server-side applied with the
+// fields in `applied`. Unlike an apply, this fails if the object already
+// exists, so two terraform operations can't both create the same object. See
+// also github.com/kubernetes/kubernetes#116156
+func createObject(
+	ctx context.Context,
+	iface dynamic.ResourceInterface,
+	obj *unstructured.Unstructured,
+	fieldManager string,
+	applied *fieldpath.Set,
+	force bool,
+) (*unstructured.Unstructured, error) {
+	created, err := iface.Create(ctx, obj, metav1.CreateOptions{FieldManager: fieldManager})
+	if err != nil {
+		return nil, err
+	}
+
+	// We have created the object, so all fields (including defaults) are owned
+	// by `fieldManager`, but with `operation: Update`. This produces a conflict
+	// when we try to server-side apply changes in `Update()`. Convert the
+	// fields in `applied` to `operation: Apply` in place, instead of applying
+	// them again.
+	result, err := patchManagedFields(ctx, iface, created, func(entries []metav1.ManagedFieldsEntry) ([]metav1.ManagedFieldsEntry, error) {
+		return generic.ApplyCreatedFields(entries, fieldManager, applied)
+	})
+	if !stderrors.Is(err, generic.ErrUnmatchedFields) {
+		return result, err
+	}
+
+	// The server tracks some of the fields differently, e.g. list items keyed
+	// by a defaulted field, so only an apply can tell which fields we own.
+	// Move our fields to `operation: Apply` first, so they don't conflict.
+	result, err = adoptManagedFields(ctx, iface, result, sets.New(fieldManager), fieldManager)
+	if err != nil {
+		return nil, err
+	}
+	// The UID ensures we don't re-create the object if it is deleted in the
+	// meantime.
+	obj = obj.DeepCopy()
+	obj.SetUID(result.GetUID())
+	return iface.Apply(ctx, obj.GetName(), obj, metav1.ApplyOptions{FieldManager: fieldManager, Force: force})
+}
+
+// stateUID returns the UID recorded in state, or the UID of obj if none was
+// recorded, e.g. by an older version of the provider.
+func stateUID(ctx context.Context, state generic.PlanOrState, obj *unstructured.Unstructured) (k8stypes.UID, diag.Diagnostics) {
+	var uid *string
+	diags := state.GetAttribute(ctx, path.Root("uid"), &uid)
+	if uid == nil {
+		return obj.GetUID(), diags
+	}
+	return k8stypes.UID(*uid), diags
+}
+
+// abandon releases all fields applied by the resource's field manager, without
+// deleting the object or removing their values.
+func (c *crdResource) abandon(ctx context.Context, state generic.PlanOrState, meta generic.ObjectMeta) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	var fieldManager string
+	diags.Append(state.GetAttribute(ctx, path.Root("field_manager"), &fieldManager)...)
+	if diags.HasError() {
+		return diags
 	}
+
+	iface := c.typeInfo.Interface(c.client, meta.Namespace)
+	obj, err := iface.Get(ctx, meta.Name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return diags
+	} else if err != nil {
+		diags.AddError("Unable to fetch resource", err.Error())
+		return diags
+	}
+	uid, uidDiags := stateUID(ctx, state, obj)
+	diags.Append(uidDiags...)
+	if diags.HasError() {
+		return diags
+	}
+	if uid != obj.GetUID() {
+		diags.AddWarning("Resource not released", objectNotDestroyedDetail)
+		return diags
+	}
+
+	// Applying an empty object would remove all the fields only we own, so
+	// keep them as fields we set without applying them instead.
+	_, err = patchManagedFields(ctx, iface, obj, func(entries []metav1.ManagedFieldsEntry) ([]metav1.ManagedFieldsEntry, error) {
+		return generic.ReleaseAppliedFields(entries, fieldManager)
+	})
+	if errors.IsNotFound(err) {
+		return diags
+	} else if err != nil {
+		diags.AddError("Unable to release resource", err.Error())
+	}
+	return diags
 }
 
 func (c *crdResource) ImportState(ctx context.Context, req tfresource.ImportStateRequest, resp *tfresource.ImportStateResponse) {
@@ -418,8 +1113,11 @@ func (c *crdResource) MoveState(ctx context.Context) []tfresource.StateMover {
 			if req.SourceTypeName != typeName("k8scrd", c.typeInfo) {
 				return
 			}
-			// No-op, only the resource name has changed
-			resp.TargetState = *req.SourceState
+			// Only the resource name has changed, attributes that have been added
+			// since are left empty.
+			for _, attr := range []string{"manifest", "field_manager", "force_conflicts"} {
+				resp.Diagnostics.Append(copyAttribute(ctx, req.SourceState, &resp.TargetState, path.Root(attr))...)
+			}
 		},
 	}}
 }
@@ -429,4 +1127,5 @@ var (
 	_ tfresource.ResourceWithConfigure   = &crdResource{}
 	_ tfresource.ResourceWithImportState = &crdResource{}
 	_ tfresource.ResourceWithMoveState   = &crdResource{}
+	_ tfresource.ResourceWithModifyPlan  = &crdResource{}
 )
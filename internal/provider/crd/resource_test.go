@@ -74,9 +74,10 @@ func TestAccResource(t *testing.T) {
 					"kubeconfig": config.StringVariable(string(kubeconfig)),
 					"update":     config.BoolVariable(true),
 				},
+				ConfigStateChecks: makeStateChecks(checkSpeck.UpdateState),
 			},
 		},
-		CheckDestroy: makeDestroyChecks(k, checkSpeck.Resources),
+		CheckDestroy: makeDestroyChecks(k, checkSpeck.Resources, checkSpeck.Abandoned),
 	})
 }
 
@@ -104,6 +105,78 @@ func TestFail(t *testing.T) {
 	})
 }
 
+func TestPlanFail(t *testing.T) {
+	kubeconfig, err := os.ReadFile(os.Getenv("KUBECONFIG"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	failCfg := fmt.Sprintf("./fixtures/%s/plan-fail.tf", os.Getenv("PROVIDER"))
+	if _, err := os.Stat(failCfg); err != nil && os.IsNotExist(err) {
+		t.Skip("No plan failure test configured")
+	} else if err != nil {
+		t.Fatal(err)
+	}
+
+	protoV6ProviderFactories := providerFactory(t)
+	resource.Test(t, resource.TestCase{
+		Steps: []resource.TestStep{{
+			ProtoV6ProviderFactories: protoV6ProviderFactories,
+			ConfigFile:               config.StaticFile(failCfg),
+			ConfigVariables:          config.Variables{"kubeconfig": config.StringVariable(string(kubeconfig))},
+			PlanOnly:                 true,
+			ExpectError:              regexp.MustCompile("Unable to dry-run resource"),
+		}},
+	})
+}
+
+func TestAdopt(t *testing.T) {
+	kubeconfig, err := os.ReadFile(os.Getenv("KUBECONFIG"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	adoptCfg := fmt.Sprintf("./fixtures/%s/adopt.tf", os.Getenv("PROVIDER"))
+	if _, err := os.Stat(adoptCfg); err != nil && os.IsNotExist(err) {
+		t.Skip("No adoption test configured")
+	} else if err != nil {
+		t.Fatal(err)
+	}
+
+	k, err := provider.MakeDynamicClient(kubeconfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rawCheckSpec, err := os.ReadFile(fmt.Sprintf("fixtures/%s/adopt.json", os.Getenv("PROVIDER")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var checkSpeck checkSpec
+	if err = json.Unmarshal(rawCheckSpec, &checkSpeck); err != nil {
+		t.Fatal(err)
+	}
+
+	protoV6ProviderFactories := providerFactory(t)
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			dataPath := fmt.Sprintf("./fixtures/%s/adopt.yaml", os.Getenv("PROVIDER"))
+			cmd := exec.Command("kubectl", "apply", "--server-side", "-f", dataPath)
+			if err := cmd.Run(); err != nil {
+				t.Fatal(err)
+			}
+		},
+		Steps: []resource.TestStep{{
+			ProtoV6ProviderFactories: protoV6ProviderFactories,
+			ConfigFile:               config.StaticFile(adoptCfg),
+			ConfigVariables:          config.Variables{"kubeconfig": config.StringVariable(string(kubeconfig))},
+			Check:                    makeOwnedChecks(k, checkSpeck.Resources, "tofu-k8s"),
+			ConfigStateChecks:        makeStateChecks(checkSpeck.State),
+		}},
+		CheckDestroy: makeDestroyChecks(k, checkSpeck.Resources, nil),
+	})
+}
+
 func patchState(wd string) error {
 	matches, err := filepath.Glob(fmt.Sprintf("%s/*/terraform.tfstate", wd))
 	if err != nil {
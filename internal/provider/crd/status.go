@@ -0,0 +1,27 @@
+package crd
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/kwohlfahrt/tf-k8s/internal/generic"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const statusDescription = "The `status` of the object, as of the last read"
+
+// setStatus sets the `status` attribute from obj, if the type has a status.
+func setStatus(ctx context.Context, typeInfo generic.TypeInfo, state *tfsdk.State, obj *unstructured.Unstructured) diag.Diagnostics {
+	if typeInfo.Status == nil {
+		return nil
+	}
+
+	status, diags := generic.StatusToValue(ctx, typeInfo, *obj)
+	if diags.HasError() {
+		return diags
+	}
+	diags.Append(state.SetAttribute(ctx, path.Root("status"), status)...)
+	return diags
+}
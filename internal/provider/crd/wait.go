@@ -0,0 +1,382 @@
+package crd
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/kwohlfahrt/tf-k8s/internal/generic"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/tools/cache"
+	watchtools "k8s.io/client-go/tools/watch"
+)
+
+// defaultTimeout applies to create, update and delete operations, including any
+// waiting, unless overridden by the `timeouts` attribute.
+const defaultTimeout time.Duration = 10 * time.Minute
+
+type waitModel struct {
+	Conditions         []waitConditionModel `tfsdk:"conditions"`
+	ObservedGeneration basetypes.BoolValue  `tfsdk:"observed_generation"`
+	Fields             []waitFieldModel     `tfsdk:"fields"`
+}
+
+type waitConditionModel struct {
+	Type   basetypes.StringValue `tfsdk:"type"`
+	Status basetypes.StringValue `tfsdk:"status"`
+}
+
+type waitFieldModel struct {
+	Path  basetypes.StringValue `tfsdk:"path"`
+	Value basetypes.StringValue `tfsdk:"value"`
+}
+
+func waitSchema() schema.Attribute {
+	return schema.SingleNestedAttribute{
+		MarkdownDescription: "Wait for the object to become ready after it is created or updated",
+		Optional:            true,
+		Attributes: map[string]schema.Attribute{
+			"conditions": schema.ListNestedAttribute{
+				MarkdownDescription: "Wait until each of `status.conditions` has the given status",
+				Optional:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"type": schema.StringAttribute{Required: true},
+						"status": schema.StringAttribute{
+							MarkdownDescription: "Defaults to `True`",
+							Optional:            true,
+						},
+					},
+				},
+			},
+			"observed_generation": schema.BoolAttribute{
+				MarkdownDescription: "Wait until `status.observedGeneration` is at least `metadata.generation`",
+				Optional:            true,
+			},
+			"fields": schema.ListNestedAttribute{
+				MarkdownDescription: "Wait until each JSONPath matches the given value, or any value if none is given",
+				Optional:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"path":  schema.StringAttribute{Required: true},
+						"value": schema.StringAttribute{Optional: true},
+					},
+				},
+			},
+		},
+	}
+}
+
+func waitForRolloutSchema() schema.Attribute {
+	return schema.BoolAttribute{
+		MarkdownDescription: "Wait for the object to finish rolling out after it is created or updated, " +
+			"e.g. until all replicas of a `Deployment` are updated and available",
+		Optional: true,
+	}
+}
+
+// pending returns a description of the first requirement that obj does not
+// satisfy, or an empty string if all are satisfied.
+func (w waitModel) pending(obj *unstructured.Unstructured) (string, error) {
+	if w.ObservedGeneration.ValueBool() {
+		observed, found, err := unstructured.NestedInt64(obj.Object, "status", "observedGeneration")
+		if err != nil {
+			return "", err
+		}
+		if !found || observed < obj.GetGeneration() {
+			return fmt.Sprintf("status.observedGeneration to reach %d", obj.GetGeneration()), nil
+		}
+	}
+
+	conditions, err := getConditions(obj)
+	if err != nil {
+		return "", err
+	}
+	for _, expected := range w.Conditions {
+		status := "True"
+		if !expected.Status.IsNull() {
+			status = expected.Status.ValueString()
+		}
+		idx := slices.IndexFunc(conditions, func(c metav1.Condition) bool { return c.Type == expected.Type.ValueString() })
+		if idx == -1 || string(conditions[idx].Status) != status {
+			return fmt.Sprintf("condition %s=%s", expected.Type.ValueString(), status), nil
+		}
+	}
+
+	for _, field := range w.Fields {
+		value, found, err := evaluatePath(obj.Object, field.Path.ValueString())
+		if err != nil {
+			return "", err
+		}
+		if field.Value.IsNull() && !found {
+			return fmt.Sprintf("%s to be set", field.Path.ValueString()), nil
+		} else if !field.Value.IsNull() && value != field.Value.ValueString() {
+			return fmt.Sprintf("%s=%s", field.Path.ValueString(), field.Value.ValueString()), nil
+		}
+	}
+
+	return "", nil
+}
+
+func getConditions(obj *unstructured.Unstructured) ([]metav1.Condition, error) {
+	rawConditions, _, err := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	if err != nil {
+		return nil, err
+	}
+
+	conditions := make([]metav1.Condition, 0, len(rawConditions))
+	for _, rawCondition := range rawConditions {
+		rawCondition, ok := rawCondition.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		var condition metav1.Condition
+		// Not all conditions have all the fields of `metav1.Condition`, so parse
+		// them field-by-field.
+		condition.Type, _, _ = unstructured.NestedString(rawCondition, "type")
+		status, _, _ := unstructured.NestedString(rawCondition, "status")
+		condition.Status = metav1.ConditionStatus(status)
+		condition.Reason, _, _ = unstructured.NestedString(rawCondition, "reason")
+		condition.Message, _, _ = unstructured.NestedString(rawCondition, "message")
+		conditions = append(conditions, condition)
+	}
+	return conditions, nil
+}
+
+func formatConditions(obj *unstructured.Unstructured) string {
+	if obj == nil {
+		return "The object was not observed"
+	}
+
+	conditions, err := getConditions(obj)
+	if err != nil || len(conditions) == 0 {
+		return "The object has no conditions"
+	}
+
+	lines := make([]string, 0, len(conditions))
+	for _, condition := range conditions {
+		line := fmt.Sprintf("%s=%s", condition.Type, condition.Status)
+		if condition.Reason != "" {
+			line = fmt.Sprintf("%s (%s)", line, condition.Reason)
+		}
+		if condition.Message != "" {
+			line = fmt.Sprintf("%s: %s", line, condition.Message)
+		}
+		lines = append(lines, line)
+	}
+	return fmt.Sprintf("Last observed conditions:\n%s", strings.Join(lines, "\n"))
+}
+
+// watchObject watches a single object until `condition` returns true, the
+// object is deleted or the context is cancelled. It returns the last observed
+// version of the object.
+func watchObject(
+	ctx context.Context,
+	iface dynamic.ResourceInterface,
+	name string,
+	condition func(*unstructured.Unstructured) (bool, error),
+) (*unstructured.Unstructured, error) {
+	fieldSelector := fmt.Sprintf("metadata.name=%s", name)
+	lw := &cache.ListWatch{
+		ListWithContextFunc: func(ctx context.Context, options metav1.ListOptions) (runtime.Object, error) {
+			options.FieldSelector = fieldSelector
+			return iface.List(ctx, options)
+		},
+		WatchFuncWithContext: func(ctx context.Context, options metav1.ListOptions) (watch.Interface, error) {
+			options.FieldSelector = fieldSelector
+			return iface.Watch(ctx, options)
+		},
+	}
+
+	var last *unstructured.Unstructured
+	_, err := watchtools.UntilWithSync(ctx, lw, &unstructured.Unstructured{}, nil, func(event watch.Event) (bool, error) {
+		obj, ok := event.Object.(*unstructured.Unstructured)
+		if !ok {
+			return false, fmt.Errorf("unexpected object type %T", event.Object)
+		}
+		last = obj
+		if event.Type == watch.Deleted {
+			return false, fmt.Errorf("%s was deleted", name)
+		}
+		return condition(obj)
+	})
+	if ctx.Err() != nil {
+		// Report the cause, rather than the error from the watch
+		err = ctx.Err()
+	}
+	return last, err
+}
+
+// waitForDeletion watches obj until it is deleted, or the context is
+// cancelled. It returns the last observed version of the object.
+func waitForDeletion(ctx context.Context, iface dynamic.ResourceInterface, obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	last := obj
+	options := metav1.ListOptions{
+		FieldSelector:   fmt.Sprintf("metadata.name=%s", obj.GetName()),
+		ResourceVersion: obj.GetResourceVersion(),
+	}
+
+	for {
+		deleted, err := watchDeletion(ctx, iface, options, obj.GetUID(), &last)
+		if err != nil || deleted {
+			return last, err
+		}
+
+		// The watch was closed or has expired, so check the current state before
+		// watching again.
+		current, err := iface.Get(ctx, obj.GetName(), metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			return last, nil
+		} else if err != nil {
+			if ctx.Err() != nil {
+				err = ctx.Err()
+			}
+			return last, err
+		}
+		if current.GetUID() != obj.GetUID() {
+			return last, nil
+		}
+		last = current
+		options.ResourceVersion = current.GetResourceVersion()
+	}
+}
+
+// watchDeletion runs a single watch, returning true if the object with the
+// given UID is deleted. It returns false without an error if the watch ends
+// before then, and should be retried.
+func watchDeletion(
+	ctx context.Context,
+	iface dynamic.ResourceInterface,
+	options metav1.ListOptions,
+	uid k8stypes.UID,
+	last **unstructured.Unstructured,
+) (bool, error) {
+	w, err := iface.Watch(ctx, options)
+	if errors.IsGone(err) || errors.IsResourceExpired(err) {
+		return false, nil
+	} else if err != nil {
+		if ctx.Err() != nil {
+			err = ctx.Err()
+		}
+		return false, err
+	}
+	defer w.Stop()
+
+	for {
+		select {
+		case event, ok := <-w.ResultChan():
+			if !ok || event.Type == watch.Error {
+				return false, nil
+			}
+			obj, ok := event.Object.(*unstructured.Unstructured)
+			if !ok {
+				return false, fmt.Errorf("unexpected object type %T", event.Object)
+			}
+			if obj.GetUID() != uid {
+				// A new object with the same name, so ours is gone
+				return true, nil
+			}
+			*last = obj
+			if event.Type == watch.Deleted {
+				return true, nil
+			}
+		case <-ctx.Done():
+			return false, ctx.Err()
+		}
+	}
+}
+
+// formatDeletion describes what may be blocking the deletion of obj.
+func formatDeletion(obj *unstructured.Unstructured) string {
+	if obj == nil {
+		return "The object was not observed"
+	}
+
+	var deletionTimestamp string
+	if ts := obj.GetDeletionTimestamp(); ts != nil {
+		deletionTimestamp = ts.UTC().Format(time.RFC3339)
+	} else {
+		deletionTimestamp = "(not set)"
+	}
+	finalizers := obj.GetFinalizers()
+	if len(finalizers) == 0 {
+		return fmt.Sprintf("Deletion timestamp: %s\nThe object has no remaining finalizers", deletionTimestamp)
+	}
+	return fmt.Sprintf("Deletion timestamp: %s\nRemaining finalizers: %s", deletionTimestamp, strings.Join(finalizers, ", "))
+}
+
+// wait waits for the object to meet the requirements in the plan. It returns
+// the last observed version of the object, or nil if there was nothing to wait
+// for.
+func (c *crdResource) wait(ctx context.Context, plan generic.PlanOrState, meta generic.ObjectMeta) (*unstructured.Unstructured, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	var wait *waitModel
+	diags.Append(plan.GetAttribute(ctx, path.Root("wait"), &wait)...)
+	var waitForRollout basetypes.BoolValue
+	diags.Append(plan.GetAttribute(ctx, path.Root("wait_for_rollout"), &waitForRollout)...)
+	var waitForOutputs basetypes.BoolValue
+	diags.Append(plan.GetAttribute(ctx, path.Root("wait_for_outputs"), &waitForOutputs)...)
+	var outputs map[string]string
+	diags.Append(plan.GetAttribute(ctx, path.Root("outputs"), &outputs)...)
+	if diags.HasError() {
+		return nil, diags
+	}
+	if !waitForOutputs.ValueBool() {
+		outputs = nil
+	}
+	if wait == nil && !waitForRollout.ValueBool() && len(outputs) == 0 {
+		return nil, diags
+	}
+
+	var pending string
+	last, err := watchObject(ctx, c.typeInfo.Interface(c.client, meta.Namespace), meta.Name, func(obj *unstructured.Unstructured) (bool, error) {
+		if waitForRollout.ValueBool() {
+			status, message, err := generic.Rollout(obj)
+			if err != nil {
+				return false, err
+			}
+			switch status {
+			case generic.RolloutFailed:
+				pending = "rollout to complete"
+				return false, fmt.Errorf("rollout failed: %s", message)
+			case generic.RolloutCurrent:
+			default:
+				pending = fmt.Sprintf("rollout to complete (%s)", message)
+				return false, nil
+			}
+		}
+
+		var err error
+		pending, err = pendingOutput(outputs, obj)
+		if err != nil || pending != "" {
+			return false, err
+		}
+
+		if wait == nil {
+			return true, nil
+		}
+		pending, err = wait.pending(obj)
+		return pending == "", err
+	})
+	if err != nil {
+		detail := fmt.Sprintf("%s\n\n%s", err.Error(), formatConditions(last))
+		if pending != "" {
+			detail = fmt.Sprintf("Waiting for %s: %s", pending, detail)
+		}
+		diags.AddError("Unable to wait for resource", detail)
+	}
+	return last, diags
+}
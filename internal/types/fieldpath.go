@@ -0,0 +1,104 @@
+package types
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
+)
+
+// AttributePath converts a field path, as formatted by structured-merge-diff
+// (e.g. `.spec.containers[name="app"].image`), to the path of the matching
+// attribute below `prefix`. List elements can't be addressed without the value
+// of the list, so the path is resolved as far as the list itself.
+func (t KubernetesObjectType) AttributePath(prefix path.Path, field string) path.Path {
+	return attributePath(t, prefix, field)
+}
+
+func attributePath(typ attr.Type, prefix path.Path, field string) path.Path {
+	rest, ok := strings.CutPrefix(field, ".")
+	if !ok {
+		return prefix
+	}
+
+	switch typ := typ.(type) {
+	case KubernetesObjectType:
+		name, rest := nextFieldName(rest)
+		for attrName, fieldName := range typ.FieldNames {
+			if fieldName == name {
+				return attributePath(typ.AttrTypes[attrName], prefix.AtName(attrName), rest)
+			}
+		}
+		return prefix
+	case KubernetesMapType:
+		// Keys may contain `.`, so a key is only delimited if there are more
+		// fields below it.
+		if _, isKubernetesType := typ.ElemType.(KubernetesType); !isKubernetesType {
+			return prefix.AtMapKey(rest)
+		}
+		key, rest := nextFieldName(rest)
+		return attributePath(typ.ElemType, prefix.AtMapKey(key), rest)
+	default:
+		return prefix
+	}
+}
+
+func nextFieldName(field string) (string, string) {
+	end := strings.IndexAny(field, ".[")
+	if end == -1 {
+		return field, ""
+	}
+	return field[:end], field[end:]
+}
+
+// FieldPath converts the path of an attribute below the object, in the same
+// syntax as a Terraform reference (e.g. `spec.replicas` or
+// `metadata.annotations["example.com/foo"]`), to a field path. List elements
+// can't be addressed.
+func (t KubernetesObjectType) FieldPath(attributePath string) (fieldpath.Path, error) {
+	var result fieldpath.Path
+	var typ attr.Type = t
+	rest := attributePath
+
+	for rest != "" {
+		var segment string
+		if strings.HasPrefix(rest, "[") {
+			end := strings.Index(rest, "]")
+			if end == -1 {
+				return nil, fmt.Errorf("unterminated [ in %s", attributePath)
+			}
+			segment, rest = rest[1:end], rest[end+1:]
+			if unquoted, err := strconv.Unquote(segment); err == nil {
+				segment = unquoted
+			}
+		} else {
+			segment, rest = nextFieldName(rest)
+		}
+		rest = strings.TrimPrefix(rest, ".")
+
+		switch t := typ.(type) {
+		case KubernetesObjectType:
+			fieldName, found := t.FieldNames[segment]
+			if !found {
+				return nil, fmt.Errorf("no attribute %s in %s", segment, attributePath)
+			}
+			result = append(result, fieldpath.PathElement{FieldName: &fieldName})
+			typ = t.AttrTypes[segment]
+		case KubernetesMapType:
+			result = append(result, fieldpath.PathElement{FieldName: &segment})
+			typ = t.ElemType
+		case KubernetesListType:
+			return nil, fmt.Errorf("list elements are not supported in %s", attributePath)
+		default:
+			return nil, fmt.Errorf("no attribute %s in %s", segment, attributePath)
+		}
+	}
+
+	if len(result) == 0 {
+		return nil, fmt.Errorf("empty path")
+	}
+	return result, nil
+}
@@ -2,6 +2,7 @@ package types
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 
 	"github.com/hashicorp/terraform-plugin-framework/attr"
@@ -97,6 +98,13 @@ func (t KubernetesUnknownType) ValueFromUnstructured(ctx context.Context, path p
 		value = basetypes.NewInt64Value(obj)
 	case float64:
 		value = basetypes.NewFloat64Value(obj)
+	case json.Number:
+		numberVal, err := numberFromUnstructured(obj)
+		if err != nil {
+			diags.Append(diag.NewAttributeErrorDiagnostic(path, "Unexpected value", err.Error()))
+			return nil, diags
+		}
+		value = basetypes.NewNumberValue(numberVal)
 	case string:
 		value = basetypes.NewStringValue(obj)
 	case bool:
@@ -211,14 +219,7 @@ func DynamicToUnstructured(obj attr.Value, path path.Path) (interface{}, diag.Di
 	case basetypes.StringValue:
 		return v.ValueString(), diags
 	case basetypes.NumberValue:
-		f := v.ValueBigFloat()
-		if f.IsInt() {
-			i, _ := f.Int64()
-			return i, diags
-		} else {
-			f, _ := f.Float64()
-			return f, diags
-		}
+		return numberToUnstructured(v.ValueBigFloat()), diags
 	case basetypes.BoolValue:
 		return v.ValueBool(), diags
 	default:
@@ -21,6 +21,10 @@ type KubernetesListType struct {
 
 	ElemType attr.Type
 	Keys     []string
+	// ListType is the `x-kubernetes-list-type` of the list, one of "atomic",
+	// "set" or "map". Atomic lists are owned as a single field, so are always
+	// read and applied as a whole.
+	ListType string
 }
 
 func (t KubernetesListType) Equal(o attr.Type) bool {
@@ -38,7 +42,7 @@ func (t KubernetesListType) String() string {
 
 func (t KubernetesListType) ValueFromDynamic(ctx context.Context, in basetypes.DynamicValue) (basetypes.DynamicValuable, diag.Diagnostics) {
 	var diags diag.Diagnostics
-	value := KubernetesListValue{DynamicValue: in, elemType: t.ElemType, keys: t.Keys}
+	value := KubernetesListValue{DynamicValue: in, elemType: t.ElemType, keys: t.Keys, listType: t.ListType}
 	if in.IsNull() || in.IsUnderlyingValueNull() || in.IsUnknown() || in.IsUnderlyingValueUnknown() {
 		return value, diags
 	}
@@ -83,7 +87,7 @@ func (t KubernetesListType) ValueFromTerraform(ctx context.Context, in tftypes.V
 }
 
 func (t KubernetesListType) ValueType(ctx context.Context) attr.Value {
-	return KubernetesListValue{elemType: t.ElemType, keys: t.Keys}
+	return KubernetesListValue{elemType: t.ElemType, keys: t.Keys, listType: t.ListType}
 }
 
 func (t KubernetesListType) ValueFromUnstructured(ctx context.Context, path path.Path, fields *fieldpath.Set, obj interface{}) (attr.Value, diag.Diagnostics) {
@@ -100,6 +104,9 @@ func (t KubernetesListType) ValueFromUnstructured(ctx context.Context, path path
 		))
 		return nil, diags
 	}
+	if t.ListType == "atomic" {
+		fields = nil
+	}
 
 	elems := make([]attr.Value, 0, len(sliceObj))
 	elemTypes := make([]attr.Type, 0, len(sliceObj))
@@ -110,7 +117,8 @@ func (t KubernetesListType) ValueFromUnstructured(ctx context.Context, path path
 		var attrDiags diag.Diagnostics
 
 		var p fieldpath.PathElement
-		if t.Keys != nil {
+		switch t.ListType {
+		case "map":
 			key := make(diffvalue.FieldList, 0, len(t.Keys))
 			obj := value.(map[string]interface{})
 			for _, k := range t.Keys {
@@ -118,7 +126,10 @@ func (t KubernetesListType) ValueFromUnstructured(ctx context.Context, path path
 				key = append(key, diffvalue.Field{Name: k, Value: v})
 			}
 			p = fieldpath.PathElement{Key: &key}
-		} else {
+		case "set":
+			v := diffvalue.NewValueInterface(value)
+			p = fieldpath.PathElement{Value: &v}
+		default:
 			p = fieldpath.PathElement{Index: &i}
 		}
 
@@ -185,7 +196,8 @@ func ListFromOpenApi(root *spec3.OpenAPI, openapi spec.Schema, path []string) (K
 
 	extensions := openapi.VendorExtensible.Extensions
 
-	var listType string
+	// Lists without an explicit type are atomic
+	listType := "atomic"
 	if rawListType, found := extensions["x-kubernetes-list-type"]; found {
 		listType = rawListType.(string)
 	}
@@ -202,7 +214,7 @@ func ListFromOpenApi(root *spec3.OpenAPI, openapi spec.Schema, path []string) (K
 		return nil, err
 	}
 
-	return KubernetesListType{DynamicType: basetypes.DynamicType{}, ElemType: elemType, Keys: keys}, nil
+	return KubernetesListType{DynamicType: basetypes.DynamicType{}, ElemType: elemType, Keys: keys, ListType: listType}, nil
 }
 
 var _ basetypes.DynamicTypable = KubernetesListType{}
@@ -213,6 +225,7 @@ type KubernetesListValue struct {
 
 	elemType attr.Type
 	keys     []string
+	listType string
 }
 
 func (v KubernetesListValue) Elements() []attr.Value {
@@ -250,12 +263,17 @@ func (v KubernetesListValue) Equal(o attr.Value) bool {
 }
 
 func (v KubernetesListValue) Type(ctx context.Context) attr.Type {
-	return KubernetesListType{DynamicType: basetypes.DynamicType{}, ElemType: v.elemType, Keys: v.keys}
+	return KubernetesListType{DynamicType: basetypes.DynamicType{}, ElemType: v.elemType, Keys: v.keys, ListType: v.listType}
 }
 
 func (v KubernetesListValue) ManagedFields(ctx context.Context, path path.Path, fields *fieldpath.Set, pe *fieldpath.PathElement) diag.Diagnostics {
 	var diags diag.Diagnostics
 
+	if v.listType == "atomic" {
+		fields.Insert([]fieldpath.PathElement{*pe})
+		return diags
+	}
+
 	fields = fields.Children.Descend(*pe)
 	for i, elem := range v.Elements() {
 		if elem.IsNull() {
@@ -265,7 +283,8 @@ func (v KubernetesListValue) ManagedFields(ctx context.Context, path path.Path,
 		fieldPath := path.AtListIndex(i)
 
 		var pathElem fieldpath.PathElement
-		if v.keys != nil {
+		switch v.listType {
+		case "map":
 			key := make(diffvalue.FieldList, 0, len(v.keys))
 
 			obj := elem.(KubernetesObjectValue)
@@ -281,7 +300,22 @@ func (v KubernetesListValue) ManagedFields(ctx context.Context, path path.Path,
 				key = append(key, diffvalue.Field{Name: k, Value: v})
 			}
 			pathElem = fieldpath.PathElement{Key: &key}
-		} else {
+		case "set":
+			var value interface{}
+			var valueDiags diag.Diagnostics
+			if kubernetesAttr, ok := elem.(KubernetesValue); ok {
+				value, valueDiags = kubernetesAttr.ToUnstructured(ctx, fieldPath)
+			} else {
+				value, valueDiags = primitiveToUnstructured(ctx, fieldPath, elem)
+			}
+			diags.Append(valueDiags...)
+			if valueDiags.HasError() {
+				continue
+			}
+			v := diffvalue.NewValueInterface(value)
+			fields.Insert([]fieldpath.PathElement{{Value: &v}})
+			continue
+		default:
 			pathElem = fieldpath.PathElement{Index: &i}
 		}
 
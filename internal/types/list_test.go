@@ -0,0 +1,133 @@
+package types_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/kwohlfahrt/tf-k8s/internal/types"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
+)
+
+func objectTypeFromJSON(t *testing.T, rawSchema string) types.KubernetesObjectType {
+	var schema spec.Schema
+	if err := json.Unmarshal([]byte(rawSchema), &schema); err != nil {
+		t.Fatal(err)
+	}
+	typ, err := types.OpenApiToTfType(nil, schema, []string{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	objectTyp, ok := typ.(types.KubernetesObjectType)
+	if !ok {
+		t.Fatalf("expected KubernetesObjectType, got %T", typ)
+	}
+	return objectTyp
+}
+
+func fieldSetFromJSON(t *testing.T, rawFields string) *fieldpath.Set {
+	fields := &fieldpath.Set{}
+	if err := fields.FromJSON(bytes.NewReader([]byte(rawFields))); err != nil {
+		t.Fatal(err)
+	}
+	return fields
+}
+
+const ownershipSchema = `{
+	"type": "object",
+	"properties": {
+		"selector": {
+			"type": "object",
+			"x-kubernetes-map-type": "atomic",
+			"properties": {
+				"matchLabels": {"type": "object", "additionalProperties": {"type": "string"}}
+			}
+		},
+		"labels": {
+			"type": "object",
+			"x-kubernetes-map-type": "atomic",
+			"additionalProperties": {"type": "string"}
+		},
+		"annotations": {"type": "object", "additionalProperties": {"type": "string"}},
+		"args": {"type": "array", "items": {"type": "string"}},
+		"finalizers": {"type": "array", "x-kubernetes-list-type": "set", "items": {"type": "string"}},
+		"other": {"type": "string"}
+	}
+}`
+
+func TestOwnershipFromUnstructured(t *testing.T) {
+	ctx := context.Background()
+	typ := objectTypeFromJSON(t, ownershipSchema)
+	fields := fieldSetFromJSON(t, `{
+		"f:selector": {},
+		"f:labels": {},
+		"f:annotations": {"f:a": {}},
+		"f:args": {},
+		"f:finalizers": {"v:\"a\"": {}}
+	}`)
+
+	obj := map[string]interface{}{
+		"selector":    map[string]interface{}{"matchLabels": map[string]interface{}{"a": "b", "c": "d"}},
+		"labels":      map[string]interface{}{"a": "b", "c": "d"},
+		"annotations": map[string]interface{}{"a": "b", "c": "d"},
+		"args":        []interface{}{"a", "b"},
+		"finalizers":  []interface{}{"a", "b"},
+		"other":       "foo",
+	}
+	expected := map[string]interface{}{
+		"selector":    map[string]interface{}{"matchLabels": map[string]interface{}{"a": "b", "c": "d"}},
+		"labels":      map[string]interface{}{"a": "b", "c": "d"},
+		"annotations": map[string]interface{}{"a": "b"},
+		"args":        []interface{}{"a", "b"},
+		"finalizers":  []interface{}{"a"},
+	}
+
+	value, diags := typ.ValueFromUnstructured(ctx, path.Empty(), fields.Leaves(), obj)
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	actual, diags := value.(types.KubernetesObjectValue).ToUnstructured(ctx, path.Empty())
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+}
+
+func TestOwnershipManagedFields(t *testing.T) {
+	ctx := context.Background()
+	typ := objectTypeFromJSON(t, ownershipSchema)
+
+	obj := map[string]interface{}{
+		"selector":    map[string]interface{}{"matchLabels": map[string]interface{}{"a": "b"}},
+		"labels":      map[string]interface{}{"a": "b"},
+		"annotations": map[string]interface{}{"a": "b"},
+		"args":        []interface{}{"a", "b"},
+		"finalizers":  []interface{}{"a"},
+	}
+	expected := fieldSetFromJSON(t, `{
+		"f:selector": {},
+		"f:labels": {},
+		"f:annotations": {"f:a": {}},
+		"f:args": {},
+		"f:finalizers": {"v:\"a\"": {}}
+	}`)
+
+	value, diags := typ.ValueFromUnstructured(ctx, path.Empty(), nil, obj)
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	fields := &fieldpath.Set{}
+	diags = value.(types.KubernetesObjectValue).ManagedFields(ctx, path.Empty(), fields, nil)
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	if !fields.Leaves().Equals(expected.Leaves()) {
+		t.Errorf("expected %v, got %v", expected, fields)
+	}
+}
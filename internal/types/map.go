@@ -19,6 +19,9 @@ type KubernetesMapType struct {
 	basetypes.DynamicType
 
 	ElemType attr.Type
+	// Atomic maps (`x-kubernetes-map-type: atomic`) are owned as a single
+	// field, so are always read and applied as a whole.
+	Atomic bool
 }
 
 func (t KubernetesMapType) Equal(o attr.Type) bool {
@@ -36,7 +39,7 @@ func (t KubernetesMapType) String() string {
 
 func (t KubernetesMapType) ValueFromDynamic(ctx context.Context, in basetypes.DynamicValue) (basetypes.DynamicValuable, diag.Diagnostics) {
 	var diags diag.Diagnostics
-	value := KubernetesMapValue{DynamicValue: in, elemType: t.ElemType}
+	value := KubernetesMapValue{DynamicValue: in, elemType: t.ElemType, atomic: t.Atomic}
 	if in.IsNull() || in.IsUnderlyingValueNull() || in.IsUnknown() || in.IsUnderlyingValueUnknown() {
 		return value, diags
 	}
@@ -81,7 +84,7 @@ func (t KubernetesMapType) ValueFromTerraform(ctx context.Context, in tftypes.Va
 }
 
 func (t KubernetesMapType) ValueType(ctx context.Context) attr.Value {
-	return KubernetesMapValue{elemType: t.ElemType}
+	return KubernetesMapValue{elemType: t.ElemType, atomic: t.Atomic}
 }
 
 func (t KubernetesMapType) ValueFromUnstructured(
@@ -103,6 +106,9 @@ func (t KubernetesMapType) ValueFromUnstructured(
 		))
 		return nil, diags
 	}
+	if t.Atomic {
+		fields = nil
+	}
 
 	elems := make(map[string]attr.Value, len(mapObj))
 	elemTypes := make(map[string]attr.Type, len(mapObj))
@@ -154,7 +160,7 @@ func MapFromOpenApi(root *spec3.OpenAPI, openapi spec.Schema, path []string) (Ku
 		return nil, err
 	}
 
-	return KubernetesMapType{DynamicType: basetypes.DynamicType{}, ElemType: elemType}, nil
+	return KubernetesMapType{DynamicType: basetypes.DynamicType{}, ElemType: elemType, Atomic: isAtomicMap(openapi)}, nil
 }
 
 func (t KubernetesMapType) Validate(ctx context.Context, path path.Path, in attr.Value, isDataSource bool) diag.Diagnostics {
@@ -186,6 +192,7 @@ type KubernetesMapValue struct {
 	basetypes.DynamicValue
 
 	elemType attr.Type
+	atomic   bool
 }
 
 func (v KubernetesMapValue) Attributes() map[string]attr.Value {
@@ -224,12 +231,17 @@ func (v KubernetesMapValue) Equal(o attr.Value) bool {
 }
 
 func (v KubernetesMapValue) Type(ctx context.Context) attr.Type {
-	return KubernetesMapType{DynamicType: basetypes.DynamicType{}, ElemType: v.elemType}
+	return KubernetesMapType{DynamicType: basetypes.DynamicType{}, ElemType: v.elemType, Atomic: v.atomic}
 }
 
 func (v KubernetesMapValue) ManagedFields(ctx context.Context, path path.Path, fields *fieldpath.Set, pe *fieldpath.PathElement) diag.Diagnostics {
 	var diags diag.Diagnostics
 
+	if v.atomic {
+		fields.Insert([]fieldpath.PathElement{*pe})
+		return diags
+	}
+
 	fields = fields.Children.Descend(*pe)
 	elems := v.Attributes()
 	for k, elem := range elems {
@@ -33,6 +33,13 @@ type KubernetesObjectType struct {
 	AttrTypes      map[string]attr.Type
 	FieldNames     map[string]string
 	RequiredFields map[string]bool
+	// ImmutableFields can't be changed once the object is created, either
+	// because of a `self == oldSelf` validation rule, or because they are
+	// known to be immutable for built-in types.
+	ImmutableFields map[string]bool
+	// Atomic objects (`x-kubernetes-map-type: atomic`) are owned as a single
+	// field, so are always read and applied as a whole.
+	Atomic bool
 }
 
 func (t KubernetesObjectType) Equal(o attr.Type) bool {
@@ -51,10 +58,12 @@ func (t KubernetesObjectType) String() string {
 func (t KubernetesObjectType) ValueFromDynamic(ctx context.Context, in basetypes.DynamicValue) (basetypes.DynamicValuable, diag.Diagnostics) {
 	var diags diag.Diagnostics
 	value := KubernetesObjectValue{
-		DynamicValue:   in,
-		attrTypes:      t.AttrTypes,
-		fieldNames:     t.FieldNames,
-		requiredFields: t.RequiredFields,
+		DynamicValue:    in,
+		attrTypes:       t.AttrTypes,
+		fieldNames:      t.FieldNames,
+		requiredFields:  t.RequiredFields,
+		immutableFields: t.ImmutableFields,
+		atomic:          t.Atomic,
 	}
 	if in.IsNull() || in.IsUnderlyingValueNull() || in.IsUnknown() || in.IsUnderlyingValueUnknown() {
 		return value, diags
@@ -107,9 +116,11 @@ func (t KubernetesObjectType) ValueFromTerraform(ctx context.Context, in tftypes
 
 func (t KubernetesObjectType) ValueType(ctx context.Context) attr.Value {
 	return KubernetesObjectValue{
-		attrTypes:      t.AttrTypes,
-		fieldNames:     t.FieldNames,
-		requiredFields: t.RequiredFields,
+		attrTypes:       t.AttrTypes,
+		fieldNames:      t.FieldNames,
+		requiredFields:  t.RequiredFields,
+		immutableFields: t.ImmutableFields,
+		atomic:          t.Atomic,
 	}
 }
 
@@ -132,6 +143,9 @@ func (t KubernetesObjectType) ValueFromUnstructured(
 		))
 		return nil, diags
 	}
+	if t.Atomic {
+		fields = nil
+	}
 
 	attributes := make(map[string]attr.Value, len(mapObj))
 	attrTypes := make(map[string]attr.Type, len(mapObj))
@@ -201,33 +215,243 @@ func (t KubernetesObjectType) SchemaType(ctx context.Context, opts SchemaTypeOpt
 func ObjectFromOpenApi(root *spec3.OpenAPI, openapi spec.Schema, path []string) (KubernetesType, error) {
 	properties := openapi.Properties
 
+	attrNames, err := attributeNames(slices.Collect(maps.Keys(properties)), path)
+	if err != nil {
+		return nil, err
+	}
+
 	attrTypes := make(map[string]attr.Type, len(properties))
 	fieldNames := make(map[string]string, len(properties))
+	immutableFields := make(map[string]bool, 0)
 	for k, property := range properties {
 		attrPath := append(path, fmt.Sprintf(".%s", k))
 		attribute, err := OpenApiToTfType(root, property, attrPath)
 		if err != nil {
 			return nil, err
 		}
-		fieldName := strcase.SnakeCase(k)
-		attrTypes[fieldName] = attribute
-		fieldNames[fieldName] = k
+		attrName := attrNames[k]
+		attrTypes[attrName] = attribute
+		fieldNames[attrName] = k
+		if isImmutable(property) {
+			immutableFields[attrName] = true
+		}
 	}
 
 	required := openapi.Required
 	requiredFields := make(map[string]bool, len(required))
 	for _, fieldName := range required {
-		requiredFields[strcase.SnakeCase(fieldName)] = true
+		if attrName, found := attrNames[fieldName]; found {
+			requiredFields[attrName] = true
+		}
 	}
 
 	return KubernetesObjectType{
-		DynamicType:    basetypes.DynamicType{},
-		AttrTypes:      attrTypes,
-		FieldNames:     fieldNames,
-		RequiredFields: requiredFields,
+		DynamicType:     basetypes.DynamicType{},
+		AttrTypes:       attrTypes,
+		FieldNames:      fieldNames,
+		RequiredFields:  requiredFields,
+		ImmutableFields: immutableFields,
+		Atomic:          isAtomicMap(openapi),
 	}, nil
 }
 
+// isImmutable checks for the validation rule used to mark immutable fields in
+// CRDs, see https://kubernetes.io/docs/tasks/extend-kubernetes/custom-resources/custom-resource-definitions/#transition-rules
+func isImmutable(openapi spec.Schema) bool {
+	rawValidations, found := openapi.Extensions["x-kubernetes-validations"]
+	if !found {
+		return false
+	}
+	validations, ok := rawValidations.([]interface{})
+	if !ok {
+		return false
+	}
+
+	for _, rawValidation := range validations {
+		validation, ok := rawValidation.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		rule, ok := validation["rule"].(string)
+		if !ok {
+			continue
+		}
+		rule = strings.Join(strings.Fields(rule), "")
+		if rule == "self==oldSelf" || rule == "oldSelf==self" {
+			return true
+		}
+	}
+	return false
+}
+
+// WithImmutableField marks the field at `fieldPath` (a list of property names)
+// as immutable, for fields that are not marked as such in the OpenAPI schema.
+func (t KubernetesObjectType) WithImmutableField(fieldPath []string) (KubernetesObjectType, error) {
+	var attrName string
+	for k, fieldName := range t.FieldNames {
+		if fieldName == fieldPath[0] {
+			attrName = k
+		}
+	}
+	if attrName == "" {
+		return t, fmt.Errorf("no property %s", fieldPath[0])
+	}
+
+	if len(fieldPath) == 1 {
+		t.ImmutableFields = maps.Clone(t.ImmutableFields)
+		if t.ImmutableFields == nil {
+			t.ImmutableFields = make(map[string]bool, 1)
+		}
+		t.ImmutableFields[attrName] = true
+		return t, nil
+	}
+
+	attrType, ok := t.AttrTypes[attrName].(KubernetesObjectType)
+	if !ok {
+		return t, fmt.Errorf("expected object at %s, got %T", fieldPath[0], t.AttrTypes[attrName])
+	}
+	attrType, err := attrType.WithImmutableField(fieldPath[1:])
+	if err != nil {
+		return t, fmt.Errorf("%s.%w", fieldPath[0], err)
+	}
+	t.AttrTypes = maps.Clone(t.AttrTypes)
+	t.AttrTypes[attrName] = attrType
+	return t, nil
+}
+
+// WithField returns a copy of the type with an additional property, for fields
+// that are not part of the schema of the manifest (e.g. `status`).
+func (t KubernetesObjectType) WithField(fieldName string, attrName string, attrType attr.Type) KubernetesObjectType {
+	t.AttrTypes = maps.Clone(t.AttrTypes)
+	if t.AttrTypes == nil {
+		t.AttrTypes = make(map[string]attr.Type, 1)
+	}
+	t.AttrTypes[attrName] = attrType
+	t.FieldNames = maps.Clone(t.FieldNames)
+	if t.FieldNames == nil {
+		t.FieldNames = make(map[string]string, 1)
+	}
+	t.FieldNames[attrName] = fieldName
+	return t
+}
+
+// ImmutableChanges returns the paths of all immutable attributes that differ
+// between `state` and `plan`.
+func (t KubernetesObjectType) ImmutableChanges(ctx context.Context, prefix path.Path, state, plan attr.Value) (path.Paths, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	var changes path.Paths
+
+	stateAttrs := objectAttributes(state)
+	planAttrs := objectAttributes(plan)
+	if stateAttrs == nil || planAttrs == nil {
+		return changes, diags
+	}
+
+	for k, attrType := range t.AttrTypes {
+		attrPath := prefix.AtName(k)
+		stateAttr, planAttr := stateAttrs[k], planAttrs[k]
+		if stateAttr == nil && planAttr == nil {
+			continue
+		}
+
+		if t.ImmutableFields[k] {
+			// Setting a field we don't own yet, e.g. to the value defaulted or
+			// assigned by the server, is not a change. If it does differ from
+			// the live value, the dry-run apply reports it.
+			if stateAttr == nil || stateAttr.IsNull() {
+				continue
+			}
+			equal, equalDiags := valuesEqual(ctx, stateAttr, planAttr)
+			diags.Append(equalDiags...)
+			if !equal {
+				changes = append(changes, attrPath)
+			}
+		} else if objectType, ok := attrType.(KubernetesObjectType); ok {
+			attrChanges, attrDiags := objectType.ImmutableChanges(ctx, attrPath, stateAttr, planAttr)
+			diags.Append(attrDiags...)
+			changes = append(changes, attrChanges...)
+		}
+	}
+
+	return changes, diags
+}
+
+func objectAttributes(value attr.Value) map[string]attr.Value {
+	objectValue, ok := value.(KubernetesObjectValue)
+	if !ok || objectValue.IsNull() || objectValue.IsUnknown() || objectValue.IsUnderlyingValueNull() || objectValue.IsUnderlyingValueUnknown() {
+		return nil
+	}
+	return objectValue.Attributes()
+}
+
+func valuesEqual(ctx context.Context, a, b attr.Value) (bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	if a == nil || b == nil {
+		return (a == nil || a.IsNull()) && (b == nil || b.IsNull()), diags
+	}
+
+	tfA, err := a.ToTerraformValue(ctx)
+	if err != nil {
+		diags.AddError("Unable to convert value", err.Error())
+		return false, diags
+	}
+	tfB, err := b.ToTerraformValue(ctx)
+	if err != nil {
+		diags.AddError("Unable to convert value", err.Error())
+		return false, diags
+	}
+	return tfA.Equal(tfB), diags
+}
+
+// attributeNames maps property names to attribute names. Properties are
+// converted to snake_case, unless several of them convert to the same name. In
+// that case, all of them use their property name unchanged. For example,
+// `fooBar` and `foo_bar` stay `fooBar` and `foo_bar`, and `IPv6` and `iPv6`
+// are both left as-is.
+func attributeNames(properties []string, path []string) (map[string]string, error) {
+	slices.Sort(properties)
+
+	bySnakeName := make(map[string][]string, len(properties))
+	for _, property := range properties {
+		snakeName := strcase.SnakeCase(property)
+		bySnakeName[snakeName] = append(bySnakeName[snakeName], property)
+	}
+
+	attrNames := make(map[string]string, len(properties))
+	for snakeName, colliding := range bySnakeName {
+		if len(colliding) == 1 {
+			attrNames[colliding[0]] = snakeName
+			continue
+		}
+		for _, property := range colliding {
+			attrNames[property] = property
+		}
+	}
+
+	// Property names are unique, so this can only fail if an unchanged property
+	// name is the snake_case name of an unrelated property.
+	byAttrName := make(map[string]string, len(attrNames))
+	for _, property := range properties {
+		attrName := attrNames[property]
+		if other, found := byAttrName[attrName]; found {
+			return nil, fmt.Errorf(
+				"properties %s and %s at %s both map to attribute %s",
+				other, property, strings.Join(path, ""), attrName,
+			)
+		}
+		byAttrName[attrName] = property
+	}
+	return attrNames, nil
+}
+
+func isAtomicMap(openapi spec.Schema) bool {
+	var mapType string
+	if rawMapType, found := openapi.Extensions["x-kubernetes-map-type"]; found {
+		mapType = rawMapType.(string)
+	}
+	return mapType == "atomic"
+}
+
 func (t KubernetesObjectType) Validate(ctx context.Context, path path.Path, in attr.Value, isDataSource bool) diag.Diagnostics {
 	var diags diag.Diagnostics
 
@@ -393,9 +617,11 @@ type KubernetesValue interface {
 type KubernetesObjectValue struct {
 	basetypes.DynamicValue
 
-	attrTypes      map[string]attr.Type
-	fieldNames     map[string]string
-	requiredFields map[string]bool
+	attrTypes       map[string]attr.Type
+	fieldNames      map[string]string
+	requiredFields  map[string]bool
+	immutableFields map[string]bool
+	atomic          bool
 }
 
 func (v KubernetesObjectValue) Equal(o attr.Value) bool {
@@ -408,10 +634,12 @@ func (v KubernetesObjectValue) Equal(o attr.Value) bool {
 
 func (v KubernetesObjectValue) Type(ctx context.Context) attr.Type {
 	return KubernetesObjectType{
-		DynamicType:    basetypes.DynamicType{},
-		AttrTypes:      v.attrTypes,
-		FieldNames:     v.fieldNames,
-		RequiredFields: v.requiredFields,
+		DynamicType:     basetypes.DynamicType{},
+		AttrTypes:       v.attrTypes,
+		FieldNames:      v.fieldNames,
+		RequiredFields:  v.requiredFields,
+		ImmutableFields: v.immutableFields,
+		Atomic:          v.atomic,
 	}
 }
 
@@ -455,6 +683,10 @@ func (v KubernetesObjectValue) ManagedFields(ctx context.Context, path path.Path
 	var diags diag.Diagnostics
 
 	if pe != nil {
+		if v.atomic {
+			fields.Insert([]fieldpath.PathElement{*pe})
+			return diags
+		}
 		fields = fields.Children.Descend(*pe)
 	}
 
@@ -0,0 +1,337 @@
+package types_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/kwohlfahrt/tf-k8s/internal/types"
+)
+
+func TestObjectAttributeNames(t *testing.T) {
+	typ := objectTypeFromJSON(t, `{
+		"type": "object",
+		"required": ["fooBar", "IPv6"],
+		"properties": {
+			"fooBar": {"type": "string"},
+			"foo_bar": {"type": "string"},
+			"IPv6": {"type": "string"},
+			"iPv6": {"type": "string"},
+			"podCIDRs": {"type": "string"},
+			"x509": {"type": "string"},
+			"http2Enabled": {"type": "boolean"},
+			"HTTP2Enabled": {"type": "boolean"},
+			"apiVersion": {"type": "string"}
+		}
+	}`)
+
+	expected := map[string]string{
+		"fooBar":       "fooBar",
+		"foo_bar":      "foo_bar",
+		"IPv6":         "IPv6",
+		"iPv6":         "iPv6",
+		"pod_cid_rs":   "podCIDRs",
+		"x509":         "x509",
+		"http2Enabled": "http2Enabled",
+		"HTTP2Enabled": "HTTP2Enabled",
+		"api_version":  "apiVersion",
+	}
+	if !reflect.DeepEqual(typ.FieldNames, expected) {
+		t.Errorf("expected %v, got %v", expected, typ.FieldNames)
+	}
+
+	expectedRequired := map[string]bool{"fooBar": true, "IPv6": true}
+	if !reflect.DeepEqual(typ.RequiredFields, expectedRequired) {
+		t.Errorf("expected %v, got %v", expectedRequired, typ.RequiredFields)
+	}
+}
+
+func TestObjectRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	typ := objectTypeFromJSON(t, `{
+		"type": "object",
+		"properties": {
+			"fooBar": {"type": "string"},
+			"foo_bar": {"type": "string"},
+			"IPv6": {"type": "string"},
+			"iPv6": {"type": "string"},
+			"podCIDRs": {"type": "array", "items": {"type": "string"}},
+			"x509": {"type": "object", "properties": {"caCert": {"type": "string"}}},
+			"http2Enabled": {"type": "boolean"},
+			"HTTP2Enabled": {"type": "boolean"}
+		}
+	}`)
+
+	obj := map[string]interface{}{
+		"fooBar":       "a",
+		"foo_bar":      "b",
+		"IPv6":         "c",
+		"iPv6":         "d",
+		"podCIDRs":     []interface{}{"10.0.0.0/8"},
+		"x509":         map[string]interface{}{"caCert": "e"},
+		"http2Enabled": true,
+		"HTTP2Enabled": false,
+	}
+
+	value, diags := typ.ValueFromUnstructured(ctx, path.Empty(), nil, obj)
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	actual, diags := value.(types.KubernetesObjectValue).ToUnstructured(ctx, path.Empty())
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	if !reflect.DeepEqual(actual, obj) {
+		t.Errorf("expected %v, got %v", obj, actual)
+	}
+}
+
+func TestObjectWithField(t *testing.T) {
+	ctx := context.Background()
+	typ := objectTypeFromJSON(t, `{
+		"type": "object",
+		"properties": {"spec": {"type": "object", "properties": {"fooBar": {"type": "string"}}}}
+	}`)
+	status := objectTypeFromJSON(t, `{
+		"type": "object",
+		"properties": {"readyReplicas": {"type": "integer"}}
+	}`)
+	withStatus := typ.WithField("status", "status", status)
+	if _, found := typ.AttrTypes["status"]; found {
+		t.Error("expected original type to be unchanged")
+	}
+
+	obj := map[string]interface{}{
+		"spec":   map[string]interface{}{"fooBar": "a"},
+		"status": map[string]interface{}{"readyReplicas": int64(1)},
+	}
+	value, diags := withStatus.ValueFromUnstructured(ctx, path.Empty(), nil, obj)
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	actual, diags := value.(types.KubernetesObjectValue).ToUnstructured(ctx, path.Empty())
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	if !reflect.DeepEqual(actual, obj) {
+		t.Errorf("expected %v, got %v", obj, actual)
+	}
+}
+
+func TestObjectImmutableChanges(t *testing.T) {
+	ctx := context.Background()
+	typ := objectTypeFromJSON(t, `{
+		"type": "object",
+		"properties": {
+			"metadata": {"type": "object", "properties": {"name": {"type": "string"}}},
+			"spec": {
+				"type": "object",
+				"properties": {
+					"storageClassName": {
+						"type": "string",
+						"x-kubernetes-validations": [{"rule": "self == oldSelf", "message": "immutable"}]
+					},
+					"template": {"type": "object", "properties": {"image": {"type": "string"}}},
+					"replicas": {"type": "integer"}
+				}
+			}
+		}
+	}`)
+	typ, err := typ.WithImmutableField([]string{"metadata", "name"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	typ, err = typ.WithImmutableField([]string{"spec", "template"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := typ.WithImmutableField([]string{"spec", "missing"}); err == nil {
+		t.Error("expected error marking missing field as immutable")
+	}
+
+	state := map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "foo"},
+		"spec": map[string]interface{}{
+			"storageClassName": "standard",
+			"template":         map[string]interface{}{"image": "busybox"},
+			"replicas":         int64(1),
+		},
+	}
+	cases := []struct {
+		plan     map[string]interface{}
+		expected path.Paths
+	}{
+		{
+			plan: map[string]interface{}{
+				"metadata": map[string]interface{}{"name": "foo"},
+				"spec": map[string]interface{}{
+					"storageClassName": "standard",
+					"template":         map[string]interface{}{"image": "busybox"},
+					"replicas":         int64(2),
+				},
+			},
+			expected: nil,
+		},
+		{
+			plan: map[string]interface{}{
+				"metadata": map[string]interface{}{"name": "bar"},
+				"spec": map[string]interface{}{
+					"storageClassName": "fast",
+					"template":         map[string]interface{}{"image": "ubuntu"},
+				},
+			},
+			expected: path.Paths{
+				path.Root("manifest").AtName("metadata").AtName("name"),
+				path.Root("manifest").AtName("spec").AtName("storage_class_name"),
+				path.Root("manifest").AtName("spec").AtName("template"),
+			},
+		},
+		{
+			plan: map[string]interface{}{
+				"metadata": map[string]interface{}{"name": "foo"},
+				"spec":     map[string]interface{}{"template": map[string]interface{}{"image": "busybox"}},
+			},
+			expected: path.Paths{path.Root("manifest").AtName("spec").AtName("storage_class_name")},
+		},
+	}
+
+	stateValue, diags := typ.ValueFromUnstructured(ctx, path.Empty(), nil, state)
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	for _, c := range cases {
+		planValue, diags := typ.ValueFromUnstructured(ctx, path.Empty(), nil, c.plan)
+		if diags.HasError() {
+			t.Fatal(diags)
+		}
+
+		changes, diags := typ.ImmutableChanges(ctx, path.Root("manifest"), stateValue, planValue)
+		if diags.HasError() {
+			t.Fatal(diags)
+		}
+		if len(changes) != len(c.expected) {
+			t.Errorf("expected %v, got %v", c.expected, changes)
+			continue
+		}
+		for _, expected := range c.expected {
+			if !changes.Contains(expected) {
+				t.Errorf("expected %v, got %v", c.expected, changes)
+			}
+		}
+	}
+
+	// Setting a field that is not in the state is not a change
+	unsetState, diags := typ.ValueFromUnstructured(ctx, path.Empty(), nil, map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "foo"},
+	})
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	planValue, diags := typ.ValueFromUnstructured(ctx, path.Empty(), nil, state)
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	changes, diags := typ.ImmutableChanges(ctx, path.Root("manifest"), unsetState, planValue)
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	if len(changes) != 0 {
+		t.Errorf("expected no changes, got %v", changes)
+	}
+}
+
+func TestObjectAttributePath(t *testing.T) {
+	typ := objectTypeFromJSON(t, `{
+		"type": "object",
+		"properties": {
+			"metadata": {
+				"type": "object",
+				"properties": {
+					"labels": {"type": "object", "additionalProperties": {"type": "string"}}
+				}
+			},
+			"spec": {
+				"type": "object",
+				"properties": {
+					"fooBar": {"type": "string"},
+					"containers": {
+						"type": "array",
+						"x-kubernetes-list-type": "map",
+						"x-kubernetes-list-map-keys": ["name"],
+						"items": {
+							"type": "object",
+							"properties": {"name": {"type": "string"}, "image": {"type": "string"}}
+						}
+					}
+				}
+			}
+		}
+	}`)
+
+	root := path.Root("manifest")
+	cases := []struct {
+		field    string
+		expected path.Path
+	}{
+		{".spec.fooBar", root.AtName("spec").AtName("foo_bar")},
+		{`.spec.containers[name="app"].image`, root.AtName("spec").AtName("containers")},
+		{".metadata.labels.app.kubernetes.io/name", root.AtName("metadata").AtName("labels").AtMapKey("app.kubernetes.io/name")},
+		{".spec.unknown", root.AtName("spec")},
+		{"", root},
+	}
+
+	for _, c := range cases {
+		actual := typ.AttributePath(root, c.field)
+		if !actual.Equal(c.expected) {
+			t.Errorf("%s: expected %s, got %s", c.field, c.expected, actual)
+		}
+	}
+}
+
+func TestObjectFieldPath(t *testing.T) {
+	typ := objectTypeFromJSON(t, `{
+		"type": "object",
+		"properties": {
+			"metadata": {
+				"type": "object",
+				"properties": {
+					"annotations": {"type": "object", "additionalProperties": {"type": "string"}}
+				}
+			},
+			"spec": {
+				"type": "object",
+				"properties": {
+					"fooBar": {"type": "string"},
+					"items": {"type": "array", "items": {"type": "string"}}
+				}
+			}
+		}
+	}`)
+
+	cases := []struct {
+		attributePath string
+		expected      string
+	}{
+		{"spec.foo_bar", ".spec.fooBar"},
+		{`metadata.annotations["example.com/foo"]`, ".metadata.annotations.example.com/foo"},
+		{"metadata.annotations.foo", ".metadata.annotations.foo"},
+		{"spec", ".spec"},
+	}
+	for _, c := range cases {
+		actual, err := typ.FieldPath(c.attributePath)
+		if err != nil {
+			t.Errorf("%s: %s", c.attributePath, err)
+			continue
+		}
+		if actual.String() != c.expected {
+			t.Errorf("%s: expected %s, got %s", c.attributePath, c.expected, actual.String())
+		}
+	}
+
+	for _, invalid := range []string{"", "spec.fooBar", "spec.items[0]", `metadata.annotations["foo"`} {
+		if _, err := typ.FieldPath(invalid); err == nil {
+			t.Errorf("%s: expected error", invalid)
+		}
+	}
+}
@@ -2,8 +2,10 @@ package types
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"math/big"
+	"strconv"
 
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
@@ -60,8 +62,7 @@ func primitiveToUnstructured(ctx context.Context, path path.Path, val attr.Value
 		return boolVal.ValueBool(), diags
 	case basetypes.NumberValuable:
 		numberVal, diags := val.ToNumberValue(ctx)
-		float, _ := numberVal.ValueBigFloat().Float64()
-		return float, diags
+		return numberToUnstructured(numberVal.ValueBigFloat()), diags
 	default:
 		return nil, diag.Diagnostics{diag.NewAttributeErrorDiagnostic(
 			path, "Unimplemented value type",
@@ -107,13 +108,11 @@ func primitiveFromUnstructured(ctx context.Context, path path.Path, typ attr.Typ
 		}
 		return typ.ValueFromBool(ctx, basetypes.NewBoolValue(boolVal))
 	case basetypes.NumberTypable:
-		floatVal, ok := val.(float64)
-		if !ok {
-			return nil, []diag.Diagnostic{diag.NewAttributeErrorDiagnostic(
-				path, "Unexpected value", fmt.Sprintf("Expected float64, got %T", val),
-			)}
+		numberVal, err := numberFromUnstructured(val)
+		if err != nil {
+			return nil, []diag.Diagnostic{diag.NewAttributeErrorDiagnostic(path, "Unexpected value", err.Error())}
 		}
-		return typ.ValueFromNumber(ctx, basetypes.NewNumberValue(big.NewFloat(floatVal)))
+		return typ.ValueFromNumber(ctx, basetypes.NewNumberValue(numberVal))
 	default:
 		return nil, diag.Diagnostics{diag.NewAttributeErrorDiagnostic(
 			path, "Unimplemented value type",
@@ -122,6 +121,38 @@ func primitiveFromUnstructured(ctx context.Context, path path.Path, typ attr.Typ
 	}
 }
 
+// Matches the precision Terraform uses for number values
+const numberPrecision uint = 512
+
+// Terraform numbers are arbitrary-precision, so convert them to the most exact
+// representation the Kubernetes JSON encoding supports: int64 for integers
+// that fit, and json.Number for anything else.
+func numberToUnstructured(val *big.Float) interface{} {
+	if val.IsInt() {
+		if intVal, accuracy := val.Int64(); accuracy == big.Exact {
+			return intVal
+		}
+		return json.Number(val.Text('f', 0))
+	}
+	return json.Number(val.Text('g', -1))
+}
+
+func numberFromUnstructured(val interface{}) (*big.Float, error) {
+	switch val := val.(type) {
+	case int64:
+		return new(big.Float).SetPrec(numberPrecision).SetInt64(val), nil
+	case float64:
+		// Use the shortest decimal representation, so that a value such as `0.1`
+		// compares equal to the same number in the configuration.
+		return numberFromUnstructured(json.Number(strconv.FormatFloat(val, 'g', -1, 64)))
+	case json.Number:
+		numberVal, _, err := big.ParseFloat(val.String(), 10, numberPrecision, big.ToNearestEven)
+		return numberVal, err
+	default:
+		return nil, fmt.Errorf("expected number, got %T", val)
+	}
+}
+
 func newNull(ctx context.Context, typ attr.Type) attr.Value {
 	// AFAIK, this can never throw an error when called this way
 	val, _ := typ.ValueFromTerraform(ctx, tftypes.NewValue(typ.TerraformType(ctx), nil))
@@ -0,0 +1,106 @@
+package types_test
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/kwohlfahrt/tf-k8s/internal/types"
+)
+
+const numberSchema = `{
+	"type": "object",
+	"properties": {
+		"value": {"type": "number"},
+		"values": {"type": "array", "items": {"type": "number"}}
+	}
+}`
+
+func parseNumber(t *testing.T, s string) *big.Float {
+	value, _, err := big.ParseFloat(s, 10, 512, big.ToNearestEven)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return value
+}
+
+func TestNumberToUnstructured(t *testing.T) {
+	ctx := context.Background()
+	typ := objectTypeFromJSON(t, numberSchema)
+
+	cases := []struct {
+		value    string
+		expected interface{}
+	}{
+		{"1", int64(1)},
+		{"9007199254740993", int64(9007199254740993)},
+		{"-9223372036854775808", int64(-9223372036854775808)},
+		{"9223372036854775808", json.Number("9223372036854775808")},
+		{"0.1", json.Number("0.1")},
+		{"1.0000000000000000000001", json.Number("1.0000000000000000000001")},
+	}
+
+	for _, c := range cases {
+		number := basetypes.NewNumberValue(parseNumber(t, c.value))
+		obj := basetypes.NewObjectValueMust(
+			map[string]attr.Type{"value": basetypes.NumberType{}},
+			map[string]attr.Value{"value": number},
+		)
+		value, diags := typ.ValueFromDynamic(ctx, basetypes.NewDynamicValue(obj))
+		if diags.HasError() {
+			t.Fatal(diags)
+		}
+
+		actual, diags := value.(types.KubernetesObjectValue).ToUnstructured(ctx, path.Empty())
+		if diags.HasError() {
+			t.Fatal(diags)
+		}
+		expected := map[string]interface{}{"value": c.expected}
+		if !reflect.DeepEqual(actual, expected) {
+			t.Errorf("expected %#v, got %#v", expected, actual)
+		}
+	}
+}
+
+func TestNumberFromUnstructured(t *testing.T) {
+	ctx := context.Background()
+	typ := objectTypeFromJSON(t, numberSchema)
+
+	obj := map[string]interface{}{
+		"value": int64(9007199254740993),
+		"values": []interface{}{
+			int64(1),
+			float64(0.1),
+			json.Number("9223372036854775808"),
+			json.Number("1.0000000000000000000001"),
+		},
+	}
+	expected := []string{"1", "0.1", "9223372036854775808", "1.0000000000000000000001"}
+
+	value, diags := typ.ValueFromUnstructured(ctx, path.Empty(), nil, obj)
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	attrs := value.(types.KubernetesObjectValue).Attributes()
+
+	actual := attrs["value"].(basetypes.NumberValue).ValueBigFloat()
+	if actual.Cmp(parseNumber(t, "9007199254740993")) != 0 {
+		t.Errorf("expected 9007199254740993, got %s", actual.Text('g', -1))
+	}
+
+	values := attrs["values"].(types.KubernetesListValue).Elements()
+	if len(values) != len(expected) {
+		t.Fatalf("expected %d values, got %d", len(expected), len(values))
+	}
+	for i, value := range values {
+		actual := value.(basetypes.NumberValue).ValueBigFloat()
+		if actual.Cmp(parseNumber(t, expected[i])) != 0 {
+			t.Errorf("expected %s, got %s", expected[i], actual.Text('g', -1))
+		}
+	}
+}